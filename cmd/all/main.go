@@ -0,0 +1,154 @@
+// Command all runs q1, q2 and todo-app from one process, for demos and
+// integration tests:
+//
+//	go run .                                  # q1 :3001, q2 :3000, todo-app :8080
+//	go run . -q1 127.0.0.1:0 -q2 127.0.0.1:0  # OS-picked ports, logged on start
+//	go run . -mount 127.0.0.1:8000            # /q1, /q2 and /todo on one port
+//
+// Each app is built the way its own main builds it, minus env-driven
+// options: q2 keeps avatars in a temporary directory and todo-app runs
+// with its development defaults. Under -mount the apps still build links
+// and Location headers from the root, so follow those by hand or use
+// separate ports when that matters.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	q1 "github.com/jabeedhexanovamedia/hello-echo/app"
+	"github.com/jabeedhexanovamedia/json-res/handlers"
+	"github.com/jabeedhexanovamedia/json-res/store"
+	todo "github.com/jabeedhexanovamedia/todo-ap/app"
+	todoconfig "github.com/jabeedhexanovamedia/todo-ap/config"
+	"github.com/jabeedhexanovamedia/todo-ap/logging"
+	"github.com/labstack/echo/v4"
+)
+
+// mounted is one app and where it is served.
+type mounted struct {
+	name    string
+	addr    string // its own listen address, without -mount
+	prefix  string // its path prefix, with -mount
+	handler http.Handler
+}
+
+func main() {
+	q1Addr := flag.String("q1", "127.0.0.1:3001", "q1 listen address")
+	q2Addr := flag.String("q2", "127.0.0.1:3000", "q2 listen address")
+	todoAddr := flag.String("todo", "127.0.0.1:8080", "todo-app listen address")
+	mount := flag.String("mount", "", "serve every app on this address under /q1, /q2 and /todo instead")
+	flag.Parse()
+
+	dir, err := os.MkdirTemp("", "all-avatars")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	apps, err := build(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	apps[0].addr, apps[1].addr, apps[2].addr = *q1Addr, *q2Addr, *todoAddr
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *mount != "" {
+		err = serve(ctx, "all", *mount, front(apps))
+	} else {
+		err = serveEach(ctx, apps)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func build(avatarDir string) ([]mounted, error) {
+	avatars, err := store.NewAvatarStore(avatarDir)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := todoconfig.New()
+	logger, err := logging.New(cfg.Log, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return []mounted{
+		{name: "q1", prefix: "/q1", handler: q1.NewServer()},
+		{name: "q2", prefix: "/q2", handler: handlers.NewRouter(store.NewUserStore(nil), avatars)},
+		{name: "todo-app", prefix: "/todo", handler: todo.NewServer(cfg, logger)},
+	}, nil
+}
+
+// front mounts each app below its prefix on one Echo instance. The apps
+// mix Echo v4 and v5, so they are mounted as plain http.Handlers.
+func front(apps []mounted) *echo.Echo {
+	e := echo.New()
+	e.HideBanner = true
+
+	index := map[string]string{}
+	for _, a := range apps {
+		h := echo.WrapHandler(http.StripPrefix(a.prefix, a.handler))
+		e.Any(a.prefix+"/*", h)
+		e.Any(a.prefix, func(c echo.Context) error {
+			return c.Redirect(http.StatusMovedPermanently, a.prefix+"/")
+		})
+		index[a.name] = a.prefix + "/"
+	}
+	e.GET("/", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, index)
+	})
+	return e
+}
+
+// serveEach serves every app on its own address until ctx is cancelled or
+// one of them fails, which stops the others.
+func serveEach(ctx context.Context, apps []mounted) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error, len(apps))
+	for _, a := range apps {
+		go func() {
+			err := serve(ctx, a.name, a.addr, a.handler)
+			if err != nil {
+				cancel()
+			}
+			errs <- err
+		}()
+	}
+
+	var all []error
+	for range apps {
+		all = append(all, <-errs)
+	}
+	return errors.Join(all...)
+}
+
+func serve(ctx context.Context, name, addr string, h http.Handler) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	srv := config.DefaultServer(port)
+	srv.BindAddr = host
+
+	if err := srv.Serve(ctx, h, slog.Default().With("app", name)); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
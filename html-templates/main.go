@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"os"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	"github.com/labstack/echo/v4"
+)
+
+type User struct {
+	Name  string
+	Email string
+}
+
+// HTML Templates: render pages with a custom echo.Renderer built on
+// html/template, with a shared layout and partials. Templates are cached in
+// production and reloaded on change otherwise (APP_ENV=production to cache).
+func main() {
+	e := echo.New()
+
+	env := os.Getenv("APP_ENV")
+	if env == "" {
+		env = "development"
+	}
+
+	renderer, err := NewTemplateRenderer("templates", env)
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	e.Renderer = renderer
+
+	e.GET("/", func(c echo.Context) error {
+		return c.Render(http.StatusOK, "home", echo.Map{
+			"Name": c.QueryParam("name"),
+		})
+	})
+
+	e.GET("/users", func(c echo.Context) error {
+		return c.Render(http.StatusOK, "users", echo.Map{
+			"Users": []User{
+				{Name: "John", Email: "john@example.com"},
+				{Name: "Jane", Email: "jane@example.com"},
+			},
+		})
+	})
+
+	srv, err := config.LoadServer(config.DefaultServer("3000"))
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	srv.Apply(e.Server)
+
+	if err := e.Start(srv.Addr()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		e.Logger.Fatal(err)
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// TemplateRenderer implements echo.Renderer with a layout + partials + page
+// structure. Every page gets its own template set, because each page defines
+// its own "content" block and sharing one set would make them overwrite each
+// other.
+type TemplateRenderer struct {
+	dir    string
+	env    string
+	reload bool // development: re-parse when a file changes
+
+	mu      sync.RWMutex
+	pages   map[string]*template.Template
+	modTime time.Time
+}
+
+// pageData is what every template sees: the handler's data plus a few
+// values the layout and partials use.
+type pageData struct {
+	Data any
+	Env  string
+	Now  time.Time
+}
+
+func NewTemplateRenderer(dir, env string) (*TemplateRenderer, error) {
+	r := &TemplateRenderer{
+		dir:    dir,
+		env:    env,
+		reload: env != "production",
+	}
+	if err := r.parse(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Render is called by c.Render(code, name, data); name is the page file name
+// without extension, e.g. "home" for pages/home.html.
+func (r *TemplateRenderer) Render(w io.Writer, name string, data interface{}, c echo.Context) error {
+	if r.reload {
+		if err := r.reloadIfChanged(); err != nil {
+			return err
+		}
+	}
+
+	r.mu.RLock()
+	tmpl, ok := r.pages[name]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("template %q not found", name)
+	}
+
+	return tmpl.ExecuteTemplate(w, "base", pageData{
+		Data: data,
+		Env:  r.env,
+		Now:  time.Now(),
+	})
+}
+
+// parse builds one template set per page from layouts/*, partials/* and the
+// page itself. In production this runs once at startup and the cache is used
+// for every request.
+func (r *TemplateRenderer) parse() error {
+	shared, err := globAll(r.dir, "layouts/*.html", "partials/*.html")
+	if err != nil {
+		return err
+	}
+	pageFiles, err := filepath.Glob(filepath.Join(r.dir, "pages", "*.html"))
+	if err != nil {
+		return err
+	}
+
+	pages := make(map[string]*template.Template, len(pageFiles))
+	for _, page := range pageFiles {
+		name := strings.TrimSuffix(filepath.Base(page), filepath.Ext(page))
+
+		tmpl, err := template.ParseFiles(append(shared, page)...)
+		if err != nil {
+			return fmt.Errorf("parse page %s: %w", name, err)
+		}
+		pages[name] = tmpl
+	}
+
+	modTime, err := latestModTime(r.dir)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.pages = pages
+	r.modTime = modTime
+	r.mu.Unlock()
+
+	return nil
+}
+
+// reloadIfChanged re-parses everything when any template file is newer than
+// the last parse. A stat per file per request is fine for development.
+func (r *TemplateRenderer) reloadIfChanged() error {
+	modTime, err := latestModTime(r.dir)
+	if err != nil {
+		return err
+	}
+
+	r.mu.RLock()
+	changed := modTime.After(r.modTime)
+	r.mu.RUnlock()
+	if !changed {
+		return nil
+	}
+
+	return r.parse()
+}
+
+func globAll(dir string, patterns ...string) ([]string, error) {
+	var files []string
+	for _, p := range patterns {
+		matches, err := filepath.Glob(filepath.Join(dir, p))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+func latestModTime(dir string) (time.Time, error) {
+	var latest time.Time
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+		return nil
+	})
+	return latest, err
+}
@@ -0,0 +1,125 @@
+// Package apierror holds the errors handlers return for expected failures,
+// and maps any error to the status and code the client sees.
+//
+// Handlers return an *Error instead of writing a response:
+//
+//	if errors.Is(err, store.ErrUserNotFound) {
+//		return apierror.Wrap(err, apierror.ErrNotFound, "user not found")
+//	}
+//
+// and the central error handler turns whatever arrives into the shared
+// envelope:
+//
+//	e := apierror.From(err)
+//	response.Error(c, e.Status, e.Code, e.Message, e.Fields)
+//
+// Like pkg/response it does not import Echo, so it works with v4 and v5.
+package apierror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/response"
+)
+
+// Error is a failure the client is told about. Status and Code say what
+// went wrong, Message is for humans and Fields names invalid input. Err is
+// the cause; it is logged but never sent.
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+	Fields  map[string]string
+	Err     error
+}
+
+// The kinds of Error. Test for them with errors.Is, which matches any
+// *Error with the same status and code:
+//
+//	errors.Is(apierror.NotFound("user not found"), apierror.ErrNotFound) // true
+var (
+	ErrBadRequest   = &Error{Status: http.StatusBadRequest, Code: "bad_request", Message: "bad request"}
+	ErrValidation   = &Error{Status: http.StatusBadRequest, Code: "validation_failed", Message: "validation failed"}
+	ErrUnauthorized = &Error{Status: http.StatusUnauthorized, Code: "unauthorized", Message: "unauthorized"}
+	ErrForbidden    = &Error{Status: http.StatusForbidden, Code: "forbidden", Message: "forbidden"}
+	ErrNotFound     = &Error{Status: http.StatusNotFound, Code: "not_found", Message: "not found"}
+	ErrConflict     = &Error{Status: http.StatusConflict, Code: "conflict", Message: "conflict"}
+	ErrInternal     = &Error{Status: http.StatusInternalServerError, Code: "internal_error", Message: "internal server error"}
+)
+
+func (e *Error) Error() string {
+	s := fmt.Sprintf("%d %s: %s", e.Status, e.Code, e.Message)
+	if e.Err != nil {
+		s += ": " + e.Err.Error()
+	}
+	return s
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Is reports whether target is the same kind of error.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Status == e.Status && t.Code == e.Code
+}
+
+// StatusCode lets Echo v5's default error handler pick the status.
+func (e *Error) StatusCode() int { return e.Status }
+
+// New returns an error for any status, with the code derived from it:
+// 413 becomes "request_entity_too_large".
+func New(status int, message string) *Error {
+	return &Error{Status: status, Code: response.CodeFor(status), Message: message}
+}
+
+// Wrap returns an error of the given kind with a client-facing message,
+// keeping err as the cause. errors.Is still finds err, so callers further
+// up can test for the original domain error.
+func Wrap(err error, kind *Error, message string) *Error {
+	return &Error{Status: kind.Status, Code: kind.Code, Message: message, Err: err}
+}
+
+func BadRequest(message string) *Error   { return Wrap(nil, ErrBadRequest, message) }
+func Unauthorized(message string) *Error { return Wrap(nil, ErrUnauthorized, message) }
+func Forbidden(message string) *Error    { return Wrap(nil, ErrForbidden, message) }
+func NotFound(message string) *Error     { return Wrap(nil, ErrNotFound, message) }
+
+// Conflict is a 409; fields point at the input that clashed, e.g.
+// {"email": "is already taken"}.
+func Conflict(message string, fields map[string]string) *Error {
+	e := Wrap(nil, ErrConflict, message)
+	e.Fields = fields
+	return e
+}
+
+// Validation is a 400 listing every invalid field and what is wrong with it.
+func Validation(fields map[string]string) *Error {
+	e := Wrap(nil, ErrValidation, ErrValidation.Message)
+	e.Fields = fields
+	return e
+}
+
+// From maps any error to the *Error that is sent to the client:
+//
+//   - an *Error anywhere in the chain is used as is
+//   - an error with a StatusCode() int method (Echo v5's HTTP errors)
+//     keeps its status, with the code and message derived from it
+//   - anything else is an unexpected failure: ErrInternal, wrapping err
+//     so it can be logged
+func From(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+
+	var sc interface{ StatusCode() int }
+	if errors.As(err, &sc) && sc.StatusCode() != 0 {
+		s := sc.StatusCode()
+		return &Error{Status: s, Code: response.CodeFor(s), Message: strings.ToLower(http.StatusText(s)), Err: err}
+	}
+
+	return Wrap(err, ErrInternal, ErrInternal.Message)
+}
@@ -0,0 +1,75 @@
+// Package buildinfo identifies a deployed binary.
+//
+// Values can be injected at build time:
+//
+//	go build -ldflags "\
+//	  -X github.com/jabeedhexanovamedia/go-echo-practice/pkg/buildinfo.Version=v1.2.0 \
+//	  -X github.com/jabeedhexanovamedia/go-echo-practice/pkg/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/jabeedhexanovamedia/go-echo-practice/pkg/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Anything left empty falls back to runtime/debug.ReadBuildInfo, which has
+// the VCS revision and time for binaries built inside a git checkout.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sync"
+)
+
+// Set via -ldflags -X.
+var (
+	Version   string
+	Commit    string
+	BuildTime string
+)
+
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	Modified  bool   `json:"modified,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+var (
+	once sync.Once
+	info Info
+)
+
+// Get returns the build info, resolved once per process.
+func Get() Info {
+	once.Do(func() {
+		info = Info{
+			Version:   Version,
+			Commit:    Commit,
+			BuildTime: BuildTime,
+			GoVersion: runtime.Version(),
+		}
+
+		if bi, ok := debug.ReadBuildInfo(); ok {
+			if info.Version == "" && bi.Main.Version != "" {
+				info.Version = bi.Main.Version
+			}
+			for _, s := range bi.Settings {
+				switch s.Key {
+				case "vcs.revision":
+					if info.Commit == "" {
+						info.Commit = s.Value
+					}
+				case "vcs.time":
+					if info.BuildTime == "" {
+						info.BuildTime = s.Value
+					}
+				case "vcs.modified":
+					info.Modified = s.Value == "true"
+				}
+			}
+		}
+
+		if info.Version == "" {
+			info.Version = "dev"
+		}
+	})
+	return info
+}
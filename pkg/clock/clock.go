@@ -0,0 +1,54 @@
+// Package clock lets code that depends on the current time take it as a
+// dependency, so tests control it instead of sleeping:
+//
+//	type Reminders struct{ clock clock.Clock }
+//
+//	func (r *Reminders) Due(t Todo) bool {
+//		return !t.DueAt.After(r.clock.Now().Add(time.Hour))
+//	}
+//
+// Production code passes clock.Real{}; tests pass a *Fake and move it with
+// Advance, which fires every timer and ticker that falls due on the way.
+package clock
+
+import "time"
+
+// Clock is the part of package time that code under test uses.
+type Clock interface {
+	Now() time.Time
+	// After is time.After.
+	After(d time.Duration) <-chan time.Time
+	// AfterFunc is time.AfterFunc.
+	AfterFunc(d time.Duration, f func()) Timer
+	// NewTicker is time.NewTicker.
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is a timer from AfterFunc.
+type Timer interface {
+	// Stop reports whether it stopped the timer before it fired.
+	Stop() bool
+}
+
+// Ticker is *time.Ticker, with its channel behind a method so a fake can
+// provide one.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+	Reset(d time.Duration)
+}
+
+// Real is the system clock.
+type Real struct{}
+
+func (Real) Now() time.Time { return time.Now() }
+
+func (Real) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+func (Real) AfterFunc(d time.Duration, f func()) Timer { return time.AfterFunc(d, f) }
+
+func (Real) NewTicker(d time.Duration) Ticker { return realTicker{time.NewTicker(d)} }
+
+type realTicker struct{ *time.Ticker }
+
+func (t realTicker) C() <-chan time.Time { return t.Ticker.C }
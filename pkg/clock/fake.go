@@ -0,0 +1,177 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Fake is a Clock that only moves when told to. It is safe for concurrent
+// use, so the code under test may wait on it in another goroutine.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*waiter
+}
+
+// waiter is a pending After, AfterFunc or ticker tick.
+type waiter struct {
+	clock  *Fake
+	at     time.Time
+	period time.Duration // > 0 for tickers
+	ch     chan time.Time
+	fn     func()
+}
+
+// NewFake returns a Fake reading start.
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// After with d <= 0 is ready at once, like time.After.
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	w := &waiter{clock: f, ch: make(chan time.Time, 1)}
+	if d <= 0 {
+		w.ch <- f.Now()
+		return w.ch
+	}
+	f.add(w, d)
+	return w.ch
+}
+
+// AfterFunc with d <= 0 starts fn in its own goroutine at once, like
+// time.AfterFunc; Stop then reports false.
+func (f *Fake) AfterFunc(d time.Duration, fn func()) Timer {
+	w := &waiter{clock: f, fn: fn}
+	if d <= 0 {
+		go fn()
+		return w
+	}
+	f.add(w, d)
+	return w
+}
+
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	w := &waiter{clock: f, period: d, ch: make(chan time.Time, 1)}
+	f.add(w, d)
+	return fakeTicker{w}
+}
+
+// Waiters is the number of pending timers and tickers. A test can poll it
+// to know a goroutine has reached its After before calling Advance.
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
+
+// Set moves the clock forward to t, firing what falls due on the way. It
+// panics if t is before Now: pending timers are relative to the current
+// time, so there is no sensible way to run the clock backwards.
+func (f *Fake) Set(t time.Time) {
+	f.Advance(t.Sub(f.Now()))
+}
+
+// Advance moves the clock forward by d. Timers and ticks that fall due fire
+// in time order, each seeing Now() as its own due time; AfterFunc callbacks
+// run before Advance returns. It panics if d is negative.
+func (f *Fake) Advance(d time.Duration) {
+	if d < 0 {
+		panic("clock: Fake cannot move back in time")
+	}
+	f.mu.Lock()
+	end := f.now.Add(d)
+	for {
+		w := f.next(end)
+		if w == nil {
+			break
+		}
+		f.now = w.at
+		if w.period > 0 {
+			w.at = w.at.Add(w.period)
+		} else {
+			f.remove(w)
+		}
+
+		if w.fn != nil {
+			// unlocked, so the callback may use the clock
+			f.mu.Unlock()
+			w.fn()
+			f.mu.Lock()
+			continue
+		}
+		// like time.Ticker, drop the tick if the last one was not read
+		select {
+		case w.ch <- f.now:
+		default:
+		}
+	}
+	if end.After(f.now) {
+		f.now = end
+	}
+	f.mu.Unlock()
+}
+
+// next returns the earliest waiter due by end, or nil.
+func (f *Fake) next(end time.Time) *waiter {
+	var first *waiter
+	for _, w := range f.waiters {
+		if !w.at.After(end) && (first == nil || w.at.Before(first.at)) {
+			first = w
+		}
+	}
+	return first
+}
+
+func (f *Fake) add(w *waiter, d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w.at = f.now.Add(d)
+	f.waiters = append(f.waiters, w)
+}
+
+// remove reports whether w was still pending. f.mu must be held.
+func (f *Fake) remove(w *waiter) bool {
+	for i, p := range f.waiters {
+		if p == w {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (w *waiter) Stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	return w.clock.remove(w)
+}
+
+// fakeTicker is a waiter with Ticker's methods; Ticker.Stop returns
+// nothing, so the waiter cannot be both.
+type fakeTicker struct{ w *waiter }
+
+func (t fakeTicker) C() <-chan time.Time { return t.w.ch }
+
+func (t fakeTicker) Stop() { t.w.Stop() }
+
+func (t fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("clock: non-positive interval for Ticker.Reset")
+	}
+	f := t.w.clock
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.remove(t.w)
+	t.w.period = d
+	t.w.at = f.now.Add(d)
+	f.waiters = append(f.waiters, t.w)
+}
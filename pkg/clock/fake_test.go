@@ -0,0 +1,161 @@
+package clock_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/clock"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestAdvanceFiresInOrder(t *testing.T) {
+	f := clock.NewFake(start)
+	var fired []string
+	record := func(name string) func() {
+		return func() { fired = append(fired, name+" "+f.Now().Sub(start).String()) }
+	}
+
+	f.AfterFunc(3*time.Second, record("func"))
+	after := f.After(2 * time.Second)
+	f.AfterFunc(time.Second, record("first"))
+	ticker := f.NewTicker(4 * time.Second)
+
+	f.Advance(5 * time.Second)
+
+	if want := []string{"first 1s", "func 3s"}; !reflect.DeepEqual(fired, want) {
+		t.Errorf("AfterFunc fired %q, want %q", fired, want)
+	}
+	if got := receive(t, after); !got.Equal(start.Add(2 * time.Second)) {
+		t.Errorf("After delivered %v, want its due time", got)
+	}
+	if got := receive(t, ticker.C()); !got.Equal(start.Add(4 * time.Second)) {
+		t.Errorf("ticker delivered %v, want its due time", got)
+	}
+	if got := f.Now(); !got.Equal(start.Add(5 * time.Second)) {
+		t.Errorf("Now() = %v, want start+5s", got)
+	}
+	if got := f.Waiters(); got != 1 {
+		t.Errorf("Waiters() = %d, want only the ticker", got)
+	}
+}
+
+func TestTickerFiresSeveralTimesInOneAdvance(t *testing.T) {
+	f := clock.NewFake(start)
+	ticker := f.NewTicker(time.Second)
+	var ticks []time.Duration
+
+	// read each tick from a callback scheduled just after it, as a
+	// goroutine ranging over C would between ticks
+	for i := 1; i <= 3; i++ {
+		f.AfterFunc(time.Duration(i)*time.Second+time.Millisecond, func() {
+			ticks = append(ticks, receive(t, ticker.C()).Sub(start))
+		})
+	}
+	f.Advance(3*time.Second + 500*time.Millisecond)
+
+	if want := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}; !reflect.DeepEqual(ticks, want) {
+		t.Errorf("ticks at %v, want %v", ticks, want)
+	}
+
+	// an unread tick is dropped, like time.Ticker, not queued
+	f.Advance(3 * time.Second)
+	if got := receive(t, ticker.C()); !got.Equal(start.Add(4 * time.Second)) {
+		t.Errorf("buffered tick = %v, want the first unread one at 4s", got.Sub(start))
+	}
+	select {
+	case got := <-ticker.C():
+		t.Errorf("second buffered tick %v", got.Sub(start))
+	default:
+	}
+}
+
+func TestStop(t *testing.T) {
+	f := clock.NewFake(start)
+	called := false
+	timer := f.AfterFunc(time.Second, func() { called = true })
+	ticker := f.NewTicker(time.Second)
+
+	if !timer.Stop() {
+		t.Error("Stop() = false for a pending timer")
+	}
+	if timer.Stop() {
+		t.Error("second Stop() = true")
+	}
+	ticker.Stop()
+	if got := f.Waiters(); got != 0 {
+		t.Errorf("Waiters() = %d after Stop", got)
+	}
+
+	f.Advance(time.Minute)
+	if called {
+		t.Error("stopped timer fired")
+	}
+	select {
+	case <-ticker.C():
+		t.Error("stopped ticker ticked")
+	default:
+	}
+
+	fired := f.AfterFunc(time.Second, func() {})
+	f.Advance(time.Second)
+	if fired.Stop() {
+		t.Error("Stop() = true for a timer that already fired")
+	}
+}
+
+func TestZeroDuration(t *testing.T) {
+	f := clock.NewFake(start)
+
+	select {
+	case got := <-f.After(0):
+		if !got.Equal(start) {
+			t.Errorf("After(0) delivered %v, want Now()", got)
+		}
+	default:
+		t.Error("After(0) not ready without Advance")
+	}
+	if got := receive(t, f.After(-time.Second)); !got.Equal(start) {
+		t.Errorf("After(-1s) delivered %v", got)
+	}
+
+	done := make(chan struct{})
+	timer := f.AfterFunc(0, func() { close(done) })
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("AfterFunc(0) did not run without Advance")
+	}
+	if timer.Stop() {
+		t.Error("Stop() = true for AfterFunc(0)")
+	}
+	if got := f.Waiters(); got != 0 {
+		t.Errorf("Waiters() = %d, want 0", got)
+	}
+}
+
+func TestSet(t *testing.T) {
+	f := clock.NewFake(start)
+	after := f.After(time.Hour)
+	f.Set(start.Add(time.Hour))
+	receive(t, after)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Set to an earlier time did not panic")
+		}
+	}()
+	f.Set(start)
+}
+
+func receive(t *testing.T, ch <-chan time.Time) time.Time {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	default:
+		t.Fatal("nothing to receive")
+		return time.Time{}
+	}
+}
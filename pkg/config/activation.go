@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+)
+
+// listenFdsStart is the first descriptor systemd passes; 0-2 are stdio.
+const listenFdsStart = 3
+
+// activated returns the listener systemd passed in with socket activation,
+// or nil when the process was started without one. With a unit pair like
+//
+//	# todo.socket
+//	[Socket]
+//	ListenStream=8080
+//
+//	# todo.service
+//	[Service]
+//	ExecStart=/usr/local/bin/todo-app
+//
+// systemd holds the port and starts the app on the first connection, and
+// connections arriving during a restart wait in the socket's backlog
+// instead of being refused. Only one socket per service is supported.
+//
+// The LISTEN_* variables are cleared, so processes the app starts do not
+// mistake the descriptor for their own.
+func activated() (net.Listener, error) {
+	pid, fds := os.Getenv("LISTEN_PID"), os.Getenv("LISTEN_FDS")
+	if pid == "" || fds == "" {
+		return nil, nil
+	}
+	// LISTEN_PID guards against inheriting the variables from a parent
+	// that was socket-activated itself
+	if pid != strconv.Itoa(os.Getpid()) {
+		return nil, nil
+	}
+	defer func() {
+		os.Unsetenv("LISTEN_PID")
+		os.Unsetenv("LISTEN_FDS")
+		os.Unsetenv("LISTEN_FDNAMES")
+	}()
+
+	n, err := strconv.Atoi(fds)
+	if err != nil || n < 1 {
+		return nil, &EnvError{Key: "LISTEN_FDS", Value: fds, Want: "a positive number of descriptors", Err: err}
+	}
+	if n > 1 {
+		return nil, fmt.Errorf("LISTEN_FDS=%d: expected one socket, got %d", n, n)
+	}
+
+	// FileListener dups the descriptor, so the original is closed here
+	f := os.NewFile(uintptr(listenFdsStart), "LISTEN_FD_3")
+	defer f.Close()
+	ln, err := net.FileListener(f)
+	if err != nil {
+		return nil, fmt.Errorf("socket activation: %w", err)
+	}
+	return ln, nil
+}
@@ -0,0 +1,108 @@
+//go:build unix
+
+package config_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"testing"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+)
+
+// TestListenActivated runs the test binary again the way systemd starts a
+// socket-activated service: the listening socket as descriptor 3 and
+// LISTEN_FDS=1. systemd sets LISTEN_PID after forking, which exec.Cmd
+// cannot do, so the child sets it to its own pid.
+func TestListenActivated(t *testing.T) {
+	if os.Getenv("TEST_ACTIVATED_CHILD") == "1" {
+		activatedChild()
+		return
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	f, err := ln.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestListenActivated$")
+	cmd.Env = append(os.Environ(), "TEST_ACTIVATED_CHILD=1", "LISTEN_FDS=1")
+	cmd.ExtraFiles = []*os.File{f}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("child: %v\n%s", err, out)
+	}
+}
+
+// activatedChild listens through config.Listen with address settings that
+// would fail if used, and checks it got descriptor 3 with the variables
+// cleared.
+func activatedChild() {
+	os.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+	s := config.Server{BindAddr: "192.0.2.1", Port: "1", Network: "tcp4"}
+	ln, err := s.Listen(context.Background())
+	if err != nil {
+		fail("Listen: %v", err)
+	}
+	defer ln.Close()
+
+	go func() {
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		if err == nil {
+			conn.Close()
+		}
+	}()
+	conn, err := ln.Accept()
+	if err != nil {
+		fail("Accept: %v", err)
+	}
+	conn.Close()
+
+	for _, key := range []string{"LISTEN_PID", "LISTEN_FDS"} {
+		if v, ok := os.LookupEnv(key); ok {
+			fail("%s=%s still set", key, v)
+		}
+	}
+}
+
+func fail(format string, args ...any) {
+	os.Stderr.WriteString(fmt.Sprintf(format, args...) + "\n")
+	os.Exit(1)
+}
+
+func TestListenActivatedIgnored(t *testing.T) {
+	// variables inherited from a socket-activated parent are not ours
+	t.Setenv("LISTEN_PID", "1")
+	t.Setenv("LISTEN_FDS", "1")
+	ln := listen(t, config.Server{BindAddr: "127.0.0.1", Port: "0"})
+	if ln.Addr().(*net.TCPAddr).Port == 0 {
+		t.Errorf("Addr() = %s", ln.Addr())
+	}
+}
+
+func TestListenActivatedBadFds(t *testing.T) {
+	for _, fds := range []string{"0", "x", "2"} {
+		t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+		t.Setenv("LISTEN_FDS", fds)
+		ln, err := config.Server{BindAddr: "127.0.0.1", Port: "0"}.Listen(context.Background())
+		if err == nil {
+			ln.Close()
+			t.Errorf("LISTEN_FDS=%s: no error", fds)
+		}
+		var envErr *config.EnvError
+		if fds != "2" && !errors.As(err, &envErr) {
+			t.Errorf("LISTEN_FDS=%s: err = %v, want an EnvError", fds, err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Package config holds the configuration pieces shared by every example
+// server: typed env getters and the listen/timeout settings.
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvValue lists the types GetEnv knows how to parse.
+type EnvValue interface {
+	string | int | int64 | float64 | bool | time.Duration | []string
+}
+
+// EnvError reports an env var that is set but cannot be parsed.
+type EnvError struct {
+	Key   string
+	Value string
+	Want  string
+	Err   error
+}
+
+func (e *EnvError) Error() string {
+	return fmt.Sprintf("%s=%q: expected %s: %v", e.Key, e.Value, e.Want, e.Err)
+}
+
+func (e *EnvError) Unwrap() error {
+	return e.Err
+}
+
+// GetEnv reads key and parses it as T, returning defaultValue when the
+// variable is unset or empty. Slices are comma-separated with spaces trimmed.
+func GetEnv[T EnvValue](key string, defaultValue T) (T, error) {
+	raw, ok := os.LookupEnv(key)
+	raw = strings.TrimSpace(raw)
+	if !ok || raw == "" {
+		return defaultValue, nil
+	}
+
+	var out T
+	var err error
+	var want string
+
+	switch p := any(&out).(type) {
+	case *string:
+		*p = raw
+	case *int:
+		want = "an integer"
+		*p, err = strconv.Atoi(raw)
+	case *int64:
+		want = "an integer"
+		*p, err = strconv.ParseInt(raw, 10, 64)
+	case *float64:
+		want = "a number"
+		*p, err = strconv.ParseFloat(raw, 64)
+	case *bool:
+		want = "a boolean (true/false, 1/0)"
+		*p, err = strconv.ParseBool(raw)
+	case *time.Duration:
+		want = "a duration like 10s or 1m"
+		*p, err = time.ParseDuration(raw)
+	case *[]string:
+		*p = splitList(raw)
+	}
+
+	if err != nil {
+		return defaultValue, &EnvError{Key: key, Value: raw, Want: want, Err: err}
+	}
+	return out, nil
+}
+
+func GetEnvInt(key string, defaultValue int) (int, error) {
+	return GetEnv(key, defaultValue)
+}
+
+func GetEnvBool(key string, defaultValue bool) (bool, error) {
+	return GetEnv(key, defaultValue)
+}
+
+func GetEnvDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	return GetEnv(key, defaultValue)
+}
+
+func GetEnvSlice(key string, defaultValue []string) ([]string, error) {
+	return GetEnv(key, defaultValue)
+}
+
+func splitList(raw string) []string {
+	parts := strings.Split(raw, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
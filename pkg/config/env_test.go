@@ -0,0 +1,62 @@
+package config_test
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+)
+
+func TestGetEnv(t *testing.T) {
+	t.Run("unset or blank gives the default", func(t *testing.T) {
+		if got, err := config.GetEnvInt("TEST_UNSET", 7); got != 7 || err != nil {
+			t.Errorf("unset = %v, %v", got, err)
+		}
+		t.Setenv("TEST_BLANK", "  ")
+		if got, err := config.GetEnvInt("TEST_BLANK", 7); got != 7 || err != nil {
+			t.Errorf("blank = %v, %v", got, err)
+		}
+	})
+
+	t.Run("parses each type", func(t *testing.T) {
+		t.Setenv("TEST_INT", " 42 ")
+		t.Setenv("TEST_BOOL", "1")
+		t.Setenv("TEST_DURATION", "1m30s")
+		t.Setenv("TEST_SLICE", "a, b,,c ")
+		t.Setenv("TEST_FLOAT", "0.5")
+
+		if got, err := config.GetEnvInt("TEST_INT", 0); got != 42 || err != nil {
+			t.Errorf("int = %v, %v", got, err)
+		}
+		if got, err := config.GetEnvBool("TEST_BOOL", false); !got || err != nil {
+			t.Errorf("bool = %v, %v", got, err)
+		}
+		if got, err := config.GetEnvDuration("TEST_DURATION", 0); got != 90*time.Second || err != nil {
+			t.Errorf("duration = %v, %v", got, err)
+		}
+		if got, err := config.GetEnvSlice("TEST_SLICE", nil); !reflect.DeepEqual(got, []string{"a", "b", "c"}) || err != nil {
+			t.Errorf("slice = %q, %v", got, err)
+		}
+		if got, err := config.GetEnv("TEST_FLOAT", 0.0); got != 0.5 || err != nil {
+			t.Errorf("float = %v, %v", got, err)
+		}
+	})
+
+	t.Run("bad value keeps the default and reports it", func(t *testing.T) {
+		t.Setenv("TEST_INT", "ten")
+		got, err := config.GetEnvInt("TEST_INT", 7)
+		if got != 7 {
+			t.Errorf("got %d, want the default 7", got)
+		}
+		var envErr *config.EnvError
+		if !errors.As(err, &envErr) || envErr.Key != "TEST_INT" || envErr.Value != "ten" {
+			t.Fatalf("err = %v, want an EnvError for TEST_INT", err)
+		}
+		if !errors.Is(err, strconv.ErrSyntax) {
+			t.Errorf("err = %v, want it to wrap strconv.ErrSyntax", err)
+		}
+	})
+}
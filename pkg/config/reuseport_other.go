@@ -0,0 +1,12 @@
+//go:build !unix
+
+package config
+
+import (
+	"errors"
+	"syscall"
+)
+
+func reusePort(network, address string, c syscall.RawConn) error {
+	return errors.New("SERVER_REUSE_PORT: SO_REUSEPORT is not supported on this platform")
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+)
+
+// Serve listens with Listen and serves h until ctx is cancelled, then stops
+// accepting and lets in-flight requests finish for up to ShutdownTimeout.
+// Any http.Handler works, so Echo v4 and v5 apps serve the same way.
+//
+// For a zero-downtime deploy start the new binary on the same port with
+// SERVER_REUSE_PORT=true, then SIGTERM the old one, with ctx from
+// signal.NotifyContext.
+func (s Server) Serve(ctx context.Context, h http.Handler, log *slog.Logger) error {
+	ln, err := s.Listen(ctx)
+	if err != nil {
+		return err
+	}
+	return s.serve(ctx, ln, h, log)
+}
+
+func (s Server) serve(ctx context.Context, ln net.Listener, h http.Handler, log *slog.Logger) error {
+	// cancelled on return, so the shutdown goroutine never outlives Serve
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	srv := &http.Server{Handler: h}
+	s.Apply(srv)
+
+	failed := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		// srv.Serve already failed, so there is nothing to drain
+		select {
+		case <-failed:
+			return
+		default:
+		}
+		log.Info("shutting down", "addr", ln.Addr().String(), "timeout", s.ShutdownTimeout)
+		sctx, cancel := context.WithTimeout(context.Background(), s.ShutdownTimeout)
+		defer cancel()
+		done <- srv.Shutdown(sctx)
+	}()
+
+	log.Info("listening", "addr", ln.Addr().String(), "reuse_port", s.ReusePort)
+	if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		close(failed)
+		return err
+	}
+	return <-done
+}
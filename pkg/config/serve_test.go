@@ -0,0 +1,114 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestServeDrainsOnCancel(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	started, release := make(chan struct{}), make(chan struct{})
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.Write([]byte("ok"))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	log, msgs := recordLog()
+	served := make(chan error, 1)
+	go func() { served <- Server{ShutdownTimeout: 5 * time.Second}.serve(ctx, ln, h, log) }()
+
+	resp := make(chan error, 1)
+	go func() {
+		res, err := http.Get("http://" + ln.Addr().String())
+		if err == nil {
+			res.Body.Close()
+			if res.StatusCode != http.StatusOK {
+				err = errors.New(res.Status)
+			}
+		}
+		resp <- err
+	}()
+	<-started
+	cancel()
+	waitFor(t, msgs, "shutting down")
+	close(release)
+
+	if err := <-resp; err != nil {
+		t.Errorf("in-flight request: %v", err)
+	}
+	if err := <-served; err != nil {
+		t.Errorf("Serve = %v, want nil after a clean shutdown", err)
+	}
+}
+
+func TestServeFailureSkipsShutdown(t *testing.T) {
+	boom := errors.New("boom")
+	log, msgs := recordLog()
+	err := Server{ShutdownTimeout: time.Second}.serve(context.Background(), failingListener{boom}, http.NotFoundHandler(), log)
+	if !errors.Is(err, boom) {
+		t.Fatalf("Serve = %v, want the accept error", err)
+	}
+
+	// the shutdown goroutine wakes up once Serve returns; give it the
+	// chance to log before checking it did not
+	timeout := time.After(100 * time.Millisecond)
+	for {
+		select {
+		case m := <-msgs:
+			if m == "shutting down" {
+				t.Fatal(`logged "shutting down" after Serve failed`)
+			}
+		case <-timeout:
+			return
+		}
+	}
+}
+
+// failingListener fails every Accept with a non-temporary error, as a
+// closed or broken socket does.
+type failingListener struct{ err error }
+
+func (l failingListener) Accept() (net.Conn, error) { return nil, l.err }
+func (l failingListener) Close() error              { return nil }
+func (l failingListener) Addr() net.Addr            { return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)} }
+
+// recordLog returns a logger that sends each message to the channel.
+func recordLog() (*slog.Logger, <-chan string) {
+	msgs := make(chan string, 16)
+	return slog.New(recorder(msgs)), msgs
+}
+
+type recorder chan string
+
+func (r recorder) Enabled(context.Context, slog.Level) bool { return true }
+func (r recorder) WithAttrs([]slog.Attr) slog.Handler       { return r }
+func (r recorder) WithGroup(string) slog.Handler            { return r }
+func (r recorder) Handle(_ context.Context, rec slog.Record) error {
+	r <- rec.Message
+	return nil
+}
+
+func waitFor(t *testing.T, msgs <-chan string, want string) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case m := <-msgs:
+			if m == want {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("no %q log", want)
+		}
+	}
+}
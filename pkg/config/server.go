@@ -0,0 +1,147 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Server is the listen address and http.Server timeouts for one example.
+//
+// BIND_ADDR picks the interface: :: or 0.0.0.0 inside containers, so the
+// port is reachable from outside, and 127.0.0.1 or ::1 for local runs.
+// Either wildcard serves IPv4 and IPv6 alike, while 127.0.0.1 is unusable
+// on an IPv6-only host. SERVER_NETWORK=tcp4 or tcp6 limits a wildcard to
+// one stack.
+//
+// ReusePort (SERVER_REUSE_PORT) sets SO_REUSEPORT, so a new binary can bind
+// the port while the old one still holds it: the kernel spreads new
+// connections over both until the old one gets SIGTERM, stops accepting
+// and drains for up to ShutdownTimeout. Every process sharing the port
+// needs it set, and on Linux they must run as the same user.
+//
+// BIND=unix:///var/run/todo.sock listens on a unix socket instead, for
+// sitting behind nginx or caddy on the same host; see Socket.
+type Server struct {
+	BindAddr  string `json:"bind_addr"`
+	Port      string `json:"port"`
+	Network   string `json:"network"`
+	ReusePort bool   `json:"reuse_port"`
+
+	Socket Socket `json:"socket"`
+
+	// ReadHeaderTimeout guards against slowloris-style clients trickling
+	// headers forever
+	ReadTimeout       time.Duration `json:"read_timeout"`
+	ReadHeaderTimeout time.Duration `json:"read_header_timeout"`
+	WriteTimeout      time.Duration `json:"write_timeout"`
+	IdleTimeout       time.Duration `json:"idle_timeout"`
+	ShutdownTimeout   time.Duration `json:"shutdown_timeout"`
+}
+
+// DefaultServer binds localhost with timeouts suitable for the examples.
+func DefaultServer(port string) Server {
+	return Server{
+		BindAddr:          "127.0.0.1",
+		Port:              port,
+		Network:           "tcp",
+		ReadTimeout:       10 * time.Second,
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      10 * time.Second,
+		IdleTimeout:       60 * time.Second,
+		ShutdownTimeout:   15 * time.Second,
+	}
+}
+
+// LoadServer overlays BIND_ADDR, SERVER_PORT (falling back to PORT, which
+// most PaaS platforms inject), BIND, SOCKET_* and SERVER_* env vars onto
+// defaults.
+func LoadServer(defaults Server) (Server, error) {
+	s := defaults
+	var errs []error
+
+	s.BindAddr, _ = GetEnv("BIND_ADDR", s.BindAddr)
+	port, _ := GetEnv("PORT", s.Port)
+	s.Port, _ = GetEnv("SERVER_PORT", port)
+
+	var err error
+	s.Network, _ = GetEnv("SERVER_NETWORK", s.Network)
+	switch s.Network {
+	case "", "tcp", "tcp4", "tcp6":
+	default:
+		errs = append(errs, &EnvError{Key: "SERVER_NETWORK", Value: s.Network, Want: "tcp, tcp4 or tcp6", Err: errors.New("unknown network")})
+	}
+	if s.ReusePort, err = GetEnvBool("SERVER_REUSE_PORT", s.ReusePort); err != nil {
+		errs = append(errs, err)
+	}
+	if s, err = loadBind(s); err != nil {
+		errs = append(errs, err)
+	}
+	if s.Socket, err = loadSocket(s.Socket); err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, t := range []struct {
+		key string
+		dst *time.Duration
+	}{
+		{"SERVER_READ_TIMEOUT", &s.ReadTimeout},
+		{"SERVER_READ_HEADER_TIMEOUT", &s.ReadHeaderTimeout},
+		{"SERVER_WRITE_TIMEOUT", &s.WriteTimeout},
+		{"SERVER_IDLE_TIMEOUT", &s.IdleTimeout},
+		{"SERVER_SHUTDOWN_TIMEOUT", &s.ShutdownTimeout},
+	} {
+		v, err := GetEnvDuration(t.key, *t.dst)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		*t.dst = v
+	}
+
+	return s, errors.Join(errs...)
+}
+
+// Addr is the host:port to listen on, e.g. 127.0.0.1:3000, [::]:8080 or
+// :8080, or unix:<path> for a socket.
+func (s Server) Addr() string {
+	if s.Socket.Path != "" {
+		return "unix:" + s.Socket.Path
+	}
+	// "[::]" as often written for IPv6 would otherwise become "[[::]]:8080"
+	host := strings.TrimSuffix(strings.TrimPrefix(s.BindAddr, "["), "]")
+	return net.JoinHostPort(host, s.Port)
+}
+
+// Listen returns the listener systemd passed in when the process was
+// socket-activated; the address settings are ignored then. Otherwise it
+// opens the socket when one is configured, or the TCP listener on Addr,
+// with SO_REUSEPORT when ReusePort is set.
+func (s Server) Listen(ctx context.Context) (net.Listener, error) {
+	if ln, err := activated(); ln != nil || err != nil {
+		return ln, err
+	}
+	if s.Socket.Path != "" {
+		return s.Socket.listen(ctx)
+	}
+
+	var lc net.ListenConfig
+	if s.ReusePort {
+		lc.Control = reusePort
+	}
+	network := s.Network
+	if network == "" {
+		network = "tcp"
+	}
+	return lc.Listen(ctx, network, s.Addr())
+}
+
+// Apply copies the timeouts onto hs.
+func (s Server) Apply(hs *http.Server) {
+	hs.ReadTimeout = s.ReadTimeout
+	hs.ReadHeaderTimeout = s.ReadHeaderTimeout
+	hs.WriteTimeout = s.WriteTimeout
+	hs.IdleTimeout = s.IdleTimeout
+}
@@ -0,0 +1,124 @@
+package config_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+)
+
+func TestAddr(t *testing.T) {
+	tests := []struct {
+		bind string
+		want string
+	}{
+		{"", ":8080"},
+		{"127.0.0.1", "127.0.0.1:8080"},
+		{"0.0.0.0", "0.0.0.0:8080"},
+		{"::", "[::]:8080"},
+		{"[::]", "[::]:8080"},
+		{"::1", "[::1]:8080"},
+		{"[::1]", "[::1]:8080"},
+		{"localhost", "localhost:8080"},
+	}
+	for _, tt := range tests {
+		s := config.Server{BindAddr: tt.bind, Port: "8080"}
+		if got := s.Addr(); got != tt.want {
+			t.Errorf("Addr() with BindAddr %q = %q, want %q", tt.bind, got, tt.want)
+		}
+	}
+
+	s := config.Server{BindAddr: "::", Port: "8080", Socket: config.Socket{Path: "/run/todo.sock"}}
+	if got := s.Addr(); got != "unix:/run/todo.sock" {
+		t.Errorf("Addr() with a socket = %q", got)
+	}
+}
+
+func TestListenNetwork(t *testing.T) {
+	tests := []struct {
+		network string
+		bind    string
+		want4   bool
+	}{
+		{"tcp4", "", true},
+		{"tcp4", "127.0.0.1", true},
+		{"tcp6", "", false},
+		{"tcp6", "::1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.network+" "+tt.bind, func(t *testing.T) {
+			if !tt.want4 {
+				requireIPv6(t)
+			}
+			ln := listen(t, config.Server{BindAddr: tt.bind, Port: "0", Network: tt.network})
+			ip := ln.Addr().(*net.TCPAddr).IP
+			if is4 := ip.To4() != nil; is4 != tt.want4 {
+				t.Errorf("listening on %s, want IPv4 = %v", ln.Addr(), tt.want4)
+			}
+		})
+	}
+
+	// an IPv6 address cannot be bound on an IPv4-only listener
+	s := config.Server{BindAddr: "::1", Port: "0", Network: "tcp4"}
+	if ln, err := s.Listen(context.Background()); err == nil {
+		ln.Close()
+		t.Error("tcp4 listened on ::1")
+	}
+}
+
+func TestLoadServerNetwork(t *testing.T) {
+	t.Setenv("SERVER_NETWORK", "udp")
+	_, err := config.LoadServer(config.DefaultServer("3000"))
+	var envErr *config.EnvError
+	if !errors.As(err, &envErr) || envErr.Key != "SERVER_NETWORK" {
+		t.Fatalf("err = %v, want an EnvError for SERVER_NETWORK", err)
+	}
+}
+
+func TestListenDualStack(t *testing.T) {
+	requireIPv6(t)
+	ln := listen(t, config.Server{BindAddr: "::", Port: "0"})
+	port := ln.Addr().(*net.TCPAddr).Port
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	for _, host := range []string{"127.0.0.1", "::1"} {
+		conn, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+		if err != nil {
+			t.Errorf("dial %s: %v", host, err)
+			continue
+		}
+		conn.Close()
+	}
+}
+
+// requireIPv6 skips the test on hosts without a usable IPv6 loopback, such
+// as containers started with IPv6 disabled.
+func requireIPv6(t *testing.T) {
+	t.Helper()
+	ln, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 unavailable: %v", err)
+	}
+	ln.Close()
+}
+
+func listen(t *testing.T, s config.Server) net.Listener {
+	t.Helper()
+	ln, err := s.Listen(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	return ln
+}
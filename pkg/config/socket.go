@@ -0,0 +1,149 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/user"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Socket is a unix socket to listen on instead of a TCP port, set with
+// BIND=unix:///var/run/todo.sock. Mode (SOCKET_MODE, octal, default 0660)
+// and Group (SOCKET_GROUP) decide who may connect; with the default mode
+// the proxy's user must be in Group, e.g. www-data for nginx.
+//
+// The socket file is removed when the listener closes, so on a clean
+// shutdown. One left behind by a crash is replaced at startup, but a
+// socket another process still answers on is not.
+type Socket struct {
+	Path  string      `json:"path,omitempty"`
+	Mode  os.FileMode `json:"mode,omitempty"`
+	Group string      `json:"group,omitempty"`
+}
+
+// DefaultSocketMode lets the owner and the owning group connect.
+const DefaultSocketMode os.FileMode = 0o660
+
+// loadBind applies BIND, which is unix://<path> or tcp://<host>:<port>.
+// The tcp form is the same as setting BIND_ADDR and SERVER_PORT.
+func loadBind(s Server) (Server, error) {
+	raw, _ := GetEnv("BIND", "")
+	if raw == "" {
+		return s, nil
+	}
+	fail := func(err error) (Server, error) {
+		return s, &EnvError{Key: "BIND", Value: raw, Want: "unix:///path/to.sock or tcp://host:port", Err: err}
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fail(err)
+	}
+	switch u.Scheme {
+	case "unix":
+		// unix:///run/x.sock has the path in Path; unix://x.sock is relative
+		path := u.Host + u.Path
+		if path == "" {
+			return fail(errors.New("missing socket path"))
+		}
+		s.Socket.Path = path
+	case "tcp":
+		host, port, err := net.SplitHostPort(u.Host)
+		if err != nil {
+			return fail(err)
+		}
+		s.BindAddr, s.Port = host, port
+	default:
+		return fail(fmt.Errorf("unsupported scheme %q", u.Scheme))
+	}
+	return s, nil
+}
+
+func loadSocket(sock Socket) (Socket, error) {
+	sock.Group, _ = GetEnv("SOCKET_GROUP", sock.Group)
+
+	raw, _ := GetEnv("SOCKET_MODE", "")
+	if raw == "" {
+		return sock, nil
+	}
+	mode, err := strconv.ParseUint(raw, 8, 32)
+	if err == nil && mode > 0o777 {
+		err = errors.New("out of range")
+	}
+	if err != nil {
+		return sock, &EnvError{Key: "SOCKET_MODE", Value: raw, Want: "octal permissions like 0660", Err: err}
+	}
+	sock.Mode = os.FileMode(mode)
+	return sock, nil
+}
+
+func (sock Socket) listen(ctx context.Context) (net.Listener, error) {
+	if err := removeStale(sock.Path); err != nil {
+		return nil, err
+	}
+
+	var lc net.ListenConfig
+	ln, err := lc.Listen(ctx, "unix", sock.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	// until these run the socket has the umask's permissions, so keep it
+	// in a directory only the app and the proxy can reach
+	mode := sock.Mode
+	if mode == 0 {
+		mode = DefaultSocketMode
+	}
+	if err := sock.chown(); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	if err := os.Chmod(sock.Path, mode); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
+
+func (sock Socket) chown() error {
+	if sock.Group == "" {
+		return nil
+	}
+	g, err := user.LookupGroup(sock.Group)
+	if err != nil {
+		return err
+	}
+	gid, err := strconv.Atoi(g.Gid)
+	if err != nil {
+		return err
+	}
+	return os.Chown(sock.Path, -1, gid)
+}
+
+// removeStale deletes a socket file nothing is listening on any more. Any
+// other kind of file at path is left alone, so Listen fails on it.
+func removeStale(path string) error {
+	fi, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil || fi.Mode()&os.ModeSocket == 0 {
+		return err
+	}
+
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err == nil {
+		conn.Close()
+		return fmt.Errorf("%s: socket is in use", path)
+	}
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return err
+	}
+	return os.Remove(path)
+}
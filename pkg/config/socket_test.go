@@ -0,0 +1,94 @@
+//go:build unix
+
+package config_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+)
+
+func TestListenSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.sock")
+	t.Setenv("BIND", "unix://"+path)
+	t.Setenv("SOCKET_MODE", "0600")
+	s, err := config.LoadServer(config.DefaultServer("3000"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Addr() != "unix:"+path {
+		t.Fatalf("Addr() = %q", s.Addr())
+	}
+
+	ln := listen(t, s)
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode()&os.ModeSocket == 0 || fi.Mode().Perm() != 0o600 {
+		t.Errorf("socket mode = %v, want a socket with 0600", fi.Mode())
+	}
+
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			conn.Write([]byte("ok"))
+			conn.Close()
+		}
+	}()
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 2)
+	if _, err := conn.Read(buf); err != nil || string(buf) != "ok" {
+		t.Errorf("read %q, %v", buf, err)
+	}
+	conn.Close()
+
+	// a second server must not take over a socket that is still answering
+	if ln2, err := s.Listen(context.Background()); err == nil {
+		ln2.Close()
+		t.Error("listened on a socket in use")
+	}
+
+	ln.Close()
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("socket file left after Close: %v", err)
+	}
+}
+
+func TestListenSocketStale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.sock")
+
+	// what a crashed process leaves behind: the file, with nobody listening
+	old, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	old.SetUnlinkOnClose(false)
+	old.Close()
+
+	listen(t, config.Server{Socket: config.Socket{Path: path}})
+}
+
+func TestListenSocketNotASocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.sock")
+	if err := os.WriteFile(path, []byte("data"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	s := config.Server{Socket: config.Socket{Path: path}}
+	if ln, err := s.Listen(context.Background()); err == nil {
+		ln.Close()
+		t.Fatal("listened over a regular file")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "data" {
+		t.Error("regular file was replaced")
+	}
+}
@@ -0,0 +1,74 @@
+// Package deprecation marks routes as on their way out. Responses from a
+// deprecated route carry the headers clients and API gateways look for,
+// and every call is logged, so the remaining callers can be found before
+// the route is removed:
+//
+//	Deprecation: @1791936000
+//	Sunset: Wed, 30 Jun 2027 00:00:00 GMT
+//	Link: </users>; rel="successor-version", <https://example.com/changelog>; rel="deprecation"
+//
+// The policy is attached where the route is registered, in Echo v4 or v5:
+//
+//	e.GET("/users1", UserMap, echo.WrapMiddleware(deprecation.Middleware(deprecation.Policy{
+//		Since:     time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC),
+//		Sunset:    time.Date(2027, 6, 30, 0, 0, 0, 0, time.UTC),
+//		Successor: "/users",
+//	})))
+package deprecation
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/logger"
+)
+
+type Policy struct {
+	// Since is when the route was deprecated (RFC 9745 Deprecation).
+	Since time.Time
+	// Sunset, if set, is when the route stops responding (RFC 8594).
+	Sunset time.Time
+	// Successor is the route to use instead, if there is one.
+	Successor string
+	// Docs links to migration notes, if there are any.
+	Docs string
+}
+
+// Middleware adds p's headers to every response of the route and logs
+// each call at warn level with the caller's user agent. The route keeps
+// working after Sunset; removing it is a separate change.
+func Middleware(p Policy) func(http.Handler) http.Handler {
+	var links []string
+	if p.Successor != "" {
+		links = append(links, "<"+p.Successor+`>; rel="successor-version"`)
+	}
+	if p.Docs != "" {
+		links = append(links, "<"+p.Docs+`>; rel="deprecation"`)
+	}
+	link := strings.Join(links, ", ")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			// a structured-field date: "@" and Unix seconds
+			h.Set("Deprecation", "@"+strconv.FormatInt(p.Since.Unix(), 10))
+			if !p.Sunset.IsZero() {
+				h.Set("Sunset", p.Sunset.UTC().Format(http.TimeFormat))
+			}
+			if link != "" {
+				h.Add("Link", link)
+			}
+
+			// one key to grep for, whether or not the logger has the path
+			attrs := []any{"deprecated_route", r.Method + " " + r.URL.Path, "user_agent", r.UserAgent()}
+			if !p.Sunset.IsZero() {
+				attrs = append(attrs, "sunset", p.Sunset.Format(time.DateOnly))
+			}
+			logger.FromContext(r.Context()).Warn("deprecated route called", attrs...)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
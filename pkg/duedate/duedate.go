@@ -0,0 +1,59 @@
+// Package duedate parses the due dates users type: RFC 3339 timestamps
+// from API clients, and English phrases from people:
+//
+//	{"title":"pay rent","due":"next friday 5pm"}
+//
+// Phrases are read with github.com/olebedev/when, relative to now in the
+// user's time zone, so "tomorrow 9am" is 9am where the user is, not where
+// the server is. The result is returned in that zone; store it as UTC.
+package duedate
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/clock"
+	"github.com/olebedev/when"
+	"github.com/olebedev/when/rules/common"
+	"github.com/olebedev/when/rules/en"
+)
+
+var ErrInvalid = errors.New(`due must be an RFC 3339 time or a phrase like "tomorrow 5pm"`)
+
+type Parser struct {
+	when  *when.Parser
+	clock clock.Clock
+}
+
+// New returns a Parser that resolves phrases against clk; nil means the
+// system clock. It is safe for concurrent use.
+func New(clk clock.Clock) *Parser {
+	if clk == nil {
+		clk = clock.Real{}
+	}
+	w := when.New(nil)
+	w.Add(en.All...)
+	w.Add(common.All...)
+	return &Parser{when: w, clock: clk}
+}
+
+// Parse reads s in loc; nil means UTC. RFC 3339 input keeps its own
+// offset. A phrase without a time of day keeps the current one, so
+// "tomorrow" is this time tomorrow. The whole of s must be a date: "buy
+// milk tomorrow" is an error rather than a due date with words ignored.
+func (p *Parser) Parse(s string, loc *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	r, err := p.when.Parse(strings.ToLower(s), p.clock.Now().In(loc))
+	if err != nil || r == nil || len(strings.TrimSpace(r.Text)) != len(s) {
+		return time.Time{}, ErrInvalid
+	}
+	return r.Time, nil
+}
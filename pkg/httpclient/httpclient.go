@@ -0,0 +1,189 @@
+// Package httpclient builds the *http.Client the examples use for outgoing
+// calls, such as webhook deliveries or OAuth token requests. Compared with
+// http.DefaultClient it:
+//
+//   - bounds every attempt and the whole call with a timeout
+//   - retries connection errors, 429 and 5xx with exponential backoff and
+//     jitter, honouring Retry-After
+//   - forwards the incoming request's X-Request-ID, so one ID follows a
+//     request across services
+//
+// It returns a plain *http.Client, so it also slots into SDKs that accept
+// one, e.g. oauth2's context.WithValue(ctx, oauth2.HTTPClient, client).
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/middleware"
+)
+
+type Config struct {
+	// Timeout bounds the whole call, retries and backoff included.
+	Timeout time.Duration
+	// AttemptTimeout bounds one attempt, until its body is closed.
+	AttemptTimeout time.Duration
+
+	// MaxRetries is how many times a failed attempt is repeated.
+	MaxRetries int
+	// BaseDelay is the backoff before the first retry; it doubles per
+	// retry up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+
+	// Transport sends the attempts; nil means a clone of
+	// http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+// DefaultConfig suits calls to other services: 3 retries within 30s,
+// 10s per attempt, backoff from 200ms to 5s.
+func DefaultConfig() Config {
+	return Config{
+		Timeout:        30 * time.Second,
+		AttemptTimeout: 10 * time.Second,
+		MaxRetries:     3,
+		BaseDelay:      200 * time.Millisecond,
+		MaxDelay:       5 * time.Second,
+	}
+}
+
+// New returns a client configured by cfg.
+func New(cfg Config) *http.Client {
+	next := cfg.Transport
+	if next == nil {
+		next = http.DefaultTransport.(*http.Transport).Clone()
+	}
+	return &http.Client{
+		Timeout:   cfg.Timeout,
+		Transport: &transport{cfg: cfg, next: next},
+	}
+}
+
+type transport struct {
+	cfg  Config
+	next http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(ctx)
+	if id := middleware.RequestIDFrom(ctx); id != "" && req.Header.Get(middleware.RequestIDHeader) == "" {
+		req.Header.Set(middleware.RequestIDHeader, id)
+	}
+
+	retryable := replayable(req)
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		res, err := t.try(req)
+		if attempt >= t.cfg.MaxRetries || !retryable || !shouldRetry(ctx, res, err) {
+			return res, err
+		}
+
+		wait := t.backoff(attempt, res)
+		if res != nil {
+			// drain so the connection can be reused
+			_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+			res.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// try sends one attempt under AttemptTimeout. The timeout must outlive
+// RoundTrip, since the caller still reads the body, so it is cancelled when
+// the body is closed.
+func (t *transport) try(req *http.Request) (*http.Response, error) {
+	if t.cfg.AttemptTimeout <= 0 {
+		return t.next.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.cfg.AttemptTimeout)
+	res, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	res.Body = &cancelBody{ReadCloser: res.Body, cancel: cancel}
+	return res, nil
+}
+
+// backoff is the wait before retry attempt+1: the server's Retry-After
+// when it sent one, otherwise a random delay up to BaseDelay*2^attempt
+// ("full jitter"), so clients that failed together do not retry together.
+// Both are capped at MaxDelay.
+func (t *transport) backoff(attempt int, res *http.Response) time.Duration {
+	if res != nil {
+		if s, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && s >= 0 {
+			return min(time.Duration(s)*time.Second, t.cfg.MaxDelay)
+		}
+	}
+	ceiling := min(t.cfg.BaseDelay<<attempt, t.cfg.MaxDelay)
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling)
+}
+
+// replayable reports whether sending req twice is safe. Idempotent methods
+// are; a POST is only when it carries an Idempotency-Key the server uses
+// to drop duplicates, as webhook deliveries do. The body must be
+// re-readable too, which http.NewRequest arranges for bytes and strings.
+func replayable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+// shouldRetry retries connection errors and attempt timeouts, 429 and
+// 5xx, except 501 Not Implemented, which will not change. A cancelled or
+// expired caller context is final.
+func shouldRetry(ctx context.Context, res *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	return res.StatusCode == http.StatusTooManyRequests ||
+		(res.StatusCode >= 500 && res.StatusCode != http.StatusNotImplemented)
+}
+
+// cancelBody releases an attempt's timeout once the body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,109 @@
+// Package idgen generates and validates resource IDs for the examples.
+//
+// IDs are UUIDv7 by default: random enough to be unguessable, and
+// time-ordered, so they sort by creation time and index well in databases.
+// ULIDs have the same properties in 26 characters instead of 36, for
+// services that put IDs in URLs or logs. Stores take a Generator, so tests
+// can pass a Fake and assert on IDs they know in advance:
+//
+//	users := store.NewUserStore(idgen.NewFake())
+//	u, _ := users.Create(models.User{Name: "Ann"})
+//	// u.Id == "00000000-0000-7000-8000-000000000001"
+package idgen
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+	"github.com/oklog/ulid/v2"
+)
+
+var ErrInvalidID = errors.New("invalid id")
+
+// Generator creates IDs and validates ones that come back from clients,
+// always in the same format.
+type Generator interface {
+	New() string
+	// Parse validates id and returns it in canonical form, or ErrInvalidID.
+	Parse(id string) (string, error)
+}
+
+// NewGenerator returns the generator called name: uuidv7 (the default when
+// name is empty) or ulid.
+func NewGenerator(name string) (Generator, error) {
+	switch strings.ToLower(name) {
+	case "uuidv7", "uuid", "":
+		return UUIDv7{}, nil
+	case "ulid":
+		return ULID{}, nil
+	default:
+		return nil, fmt.Errorf("unknown id format %q (want uuidv7 or ulid)", name)
+	}
+}
+
+// UUIDv7 generates lowercase UUIDv7 strings.
+type UUIDv7 struct{}
+
+func (UUIDv7) New() string {
+	return uuid.Must(uuid.NewV7()).String()
+}
+
+// Parse accepts any UUID, not only v7, so IDs issued before a switch to v7
+// stay valid.
+func (UUIDv7) Parse(id string) (string, error) {
+	u, err := uuid.Parse(id)
+	if err != nil {
+		return "", ErrInvalidID
+	}
+	return u.String(), nil
+}
+
+// ULID generates uppercase ULID strings. IDs made within the same
+// millisecond increase monotonically, so they sort in creation order too.
+type ULID struct{}
+
+func (ULID) New() string {
+	return ulid.Make().String()
+}
+
+// Parse accepts either case, since Crockford base32 is case-insensitive,
+// and returns uppercase.
+func (ULID) Parse(id string) (string, error) {
+	u, err := ulid.ParseStrict(id)
+	if err != nil {
+		return "", ErrInvalidID
+	}
+	return u.String(), nil
+}
+
+// Fake is a deterministic Generator for tests. It returns UUID-shaped IDs
+// counting up from ...000000000001, so they pass UUIDv7.Parse and sort in
+// creation order like real ones. It is safe for concurrent use.
+type Fake struct {
+	n atomic.Uint64
+}
+
+func NewFake() *Fake {
+	return &Fake{}
+}
+
+func (f *Fake) New() string {
+	return fmt.Sprintf("00000000-0000-7000-8000-%012d", f.n.Add(1))
+}
+
+func (f *Fake) Parse(id string) (string, error) {
+	return UUIDv7{}.Parse(id)
+}
+
+// New returns a new UUIDv7 string.
+func New() string {
+	return UUIDv7{}.New()
+}
+
+// Parse validates a UUID and returns it in canonical lowercase form.
+func Parse(id string) (string, error) {
+	return UUIDv7{}.Parse(id)
+}
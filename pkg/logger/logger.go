@@ -0,0 +1,136 @@
+// Package logger builds the slog.Logger every example logs through, and
+// carries it in the context so each layer logs with the same fields:
+//
+//	e.Use(echo.WrapMiddleware(middleware.RequestID))
+//	e.Use(echo.WrapMiddleware(logger.Middleware(log)))
+//	...
+//	func (r *Repo) Save(ctx context.Context, t Todo) error {
+//		logger.FromContext(ctx).Debug("saving todo", "id", t.ID)
+//	}
+//
+// logs "request_id=0189... method=POST path=/todos msg=saving todo id=42"
+// without the repository knowing about HTTP. A worker does the same with
+// WithContext(ctx, log.With("job", name)).
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/middleware"
+)
+
+// Field names shared by every log line that has them. request_id matches
+// what Echo's RequestLogger writes.
+const (
+	KeyRequestID = "request_id"
+	KeyTenant    = "tenant"
+)
+
+type Config struct {
+	Level  string `json:"level"`  // debug, info, warn or error
+	Format string `json:"format"` // text or json
+
+	// Output defaults to os.Stdout.
+	Output io.Writer `json:"-"`
+}
+
+// LoadConfig reads LOG_LEVEL (default info) and LOG_FORMAT (default text).
+func LoadConfig() (Config, error) {
+	level, err := config.GetEnv("LOG_LEVEL", "info")
+	if err != nil {
+		return Config{}, err
+	}
+	format, err := config.GetEnv("LOG_FORMAT", "text")
+	if err != nil {
+		return Config{}, err
+	}
+	return Config{Level: level, Format: format}, nil
+}
+
+// New builds the logger described by cfg. The returned LevelVar holds its
+// level; setting it changes the level at runtime.
+func New(cfg Config) (*slog.Logger, *slog.LevelVar, error) {
+	level, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, nil, err
+	}
+	lv := new(slog.LevelVar)
+	lv.Set(level)
+
+	out := cfg.Output
+	if out == nil {
+		out = os.Stdout
+	}
+	opts := &slog.HandlerOptions{Level: lv}
+
+	var h slog.Handler
+	switch strings.ToLower(cfg.Format) {
+	case "json":
+		h = slog.NewJSONHandler(out, opts)
+	case "text", "":
+		h = slog.NewTextHandler(out, opts)
+	default:
+		return nil, nil, fmt.Errorf("unknown log format %q (want json or text)", cfg.Format)
+	}
+	return slog.New(h), lv, nil
+}
+
+// ParseLevel accepts debug, info, warn and error (case-insensitive). An
+// empty string is info.
+func ParseLevel(s string) (slog.Level, error) {
+	if s == "" {
+		return slog.LevelInfo, nil
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("unknown log level %q (want debug, info, warn or error)", s)
+	}
+	return level, nil
+}
+
+type loggerKey struct{}
+
+// WithContext returns ctx carrying l, for FromContext further down.
+func WithContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the logger stored in ctx, or slog.Default(), with
+// the request ID and tenant of ctx attached when there are any.
+func FromContext(ctx context.Context) *slog.Logger {
+	l, ok := ctx.Value(loggerKey{}).(*slog.Logger)
+	if !ok {
+		l = slog.Default()
+	}
+
+	var attrs []any
+	if id := middleware.RequestIDFrom(ctx); id != "" {
+		attrs = append(attrs, KeyRequestID, id)
+	}
+	if t := middleware.TenantFrom(ctx); t != "" {
+		attrs = append(attrs, KeyTenant, t)
+	}
+	if attrs == nil {
+		return l
+	}
+	return l.With(attrs...)
+}
+
+// Middleware stores l, with the request's method and path, in every
+// request's context. Register it after middleware.RequestID so the ID is
+// there to pick up.
+func Middleware(l *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rl := l.With("method", r.Method, "path", r.URL.Path)
+			next.ServeHTTP(w, r.WithContext(WithContext(r.Context(), rl)))
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package markdown
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	r := New()
+	tests := []struct {
+		name    string
+		src     string
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "emphasis",
+			src:  "**ship** it",
+			want: []string{"<p><strong>ship</strong> it</p>"},
+		},
+		{
+			name: "task list",
+			src:  "- [x] done\n- [ ] todo",
+			want: []string{`<input checked="" disabled="" type="checkbox"`, `<input disabled="" type="checkbox"`},
+		},
+		{
+			name:    "raw HTML",
+			src:     `<script>alert(1)</script><input type="text" name="q">`,
+			notWant: []string{"<script", "<input"},
+		},
+		{
+			name:    "javascript link",
+			src:     "[x](javascript:alert(1))",
+			notWant: []string{"javascript:"},
+		},
+		{
+			name: "external link",
+			src:  "<https://example.com>",
+			want: []string{`href="https://example.com"`, `rel="nofollow noopener"`, `target="_blank"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := r.Render(tt.src)
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("Render(%q) = %q, want it to contain %q", tt.src, got, w)
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(got, w) {
+					t.Errorf("Render(%q) = %q, must not contain %q", tt.src, got, w)
+				}
+			}
+		})
+	}
+}
+
+// TestInputType feeds the policy directly, as if goldmark had let an
+// <input> through: only checkboxes survive. Without its type an <input>
+// has no attributes left, and bluemonday drops it altogether.
+func TestInputType(t *testing.T) {
+	r := New()
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{`<input type="checkbox" checked disabled>`, `<input type="checkbox" checked="" disabled="">`},
+		{`<input type="text" value="x">`, ""},
+		{`<input type="checkbox text">`, ""},
+		{`<input type="password">`, ""},
+		{`<input type="image" src="https://example.com/x.png">`, ""},
+	}
+	for _, tt := range tests {
+		if got := r.policy.Sanitize(tt.in); got != tt.want {
+			t.Errorf("Sanitize(%s) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRenderTooLarge(t *testing.T) {
+	src := strings.Repeat("*a* ", MaxSize/4+1) + "<b>"
+	got := New().Render(src)
+	if !strings.HasPrefix(got, "<pre>") || strings.Contains(got, "<em>") || strings.Contains(got, "<b>") {
+		t.Errorf("oversized source was rendered: %.80s...", got)
+	}
+}
+
+func TestWantsHTML(t *testing.T) {
+	for q, want := range map[string]bool{"render=html": true, "render=HTML": true, "render=text": false, "": false} {
+		v, _ := url.ParseQuery(q)
+		if got := WantsHTML(v); got != want {
+			t.Errorf("WantsHTML(%q) = %v, want %v", q, got, want)
+		}
+	}
+}
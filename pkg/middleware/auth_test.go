@@ -0,0 +1,121 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/middleware"
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/testutil"
+)
+
+func TestKeys(t *testing.T) {
+	valid := middleware.Keys("alpha", "beta")
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"alpha", true},
+		{"beta", true},
+		{"", false},
+		{"alp", false},
+		{"alphabet", false},
+		{"ALPHA", false},
+	}
+	for _, tt := range tests {
+		if got := valid(tt.key); got != tt.want {
+			t.Errorf("Keys(alpha, beta)(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+
+	if middleware.Keys()("") {
+		t.Error("Keys() accepted the empty key")
+	}
+}
+
+func TestBearerAuth(t *testing.T) {
+	mw := middleware.BearerAuth(middleware.Keys("s3cret"))
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"valid", "Bearer s3cret", http.StatusOK},
+		{"scheme is case-insensitive", "bearer s3cret", http.StatusOK},
+		{"surrounding spaces", "Bearer  s3cret ", http.StatusOK},
+		{"missing", "", http.StatusUnauthorized},
+		{"wrong token", "Bearer nope", http.StatusUnauthorized},
+		{"empty token", "Bearer ", http.StatusUnauthorized},
+		{"no scheme", "s3cret", http.StatusUnauthorized},
+		{"basic scheme", "Basic s3cret", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec, got := run(mw, req)
+
+			if tt.want == http.StatusOK {
+				testutil.AssertStatus(t, rec, http.StatusOK)
+				if got == nil {
+					t.Error("handler not reached")
+				}
+				return
+			}
+			body := testutil.AssertError(t, rec, http.StatusUnauthorized, "unauthorized")
+			if body.Message != "missing or invalid bearer token" {
+				t.Errorf("message = %q", body.Message)
+			}
+			testutil.AssertHeader(t, rec, "WWW-Authenticate", `Bearer realm="api"`)
+			if got != nil {
+				t.Error("handler reached")
+			}
+		})
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	mw := middleware.APIKey("X-API-Key", middleware.Keys("k1", "k2"))
+	tests := []struct {
+		name string
+		key  string
+		want int
+	}{
+		{"first key", "k1", http.StatusOK},
+		{"second key", "k2", http.StatusOK},
+		{"missing", "", http.StatusUnauthorized},
+		{"wrong", "k3", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.key != "" {
+				req.Header.Set("X-API-Key", tt.key)
+			}
+			rec, got := run(mw, req)
+
+			if tt.want == http.StatusOK {
+				testutil.AssertStatus(t, rec, http.StatusOK)
+				if got == nil {
+					t.Error("handler not reached")
+				}
+				return
+			}
+			body := testutil.AssertError(t, rec, http.StatusUnauthorized, "unauthorized")
+			if body.Message != "missing or invalid X-API-Key" {
+				t.Errorf("message = %q", body.Message)
+			}
+			if got != nil {
+				t.Error("handler reached")
+			}
+		})
+	}
+
+	// the key is only read from the configured header
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer k1")
+	rec, _ := run(mw, req)
+	testutil.AssertStatus(t, rec, http.StatusUnauthorized)
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// RequireContentType rejects POST, PUT and PATCH requests whose
+// Content-Type is not one of types (415) or whose body is empty (400).
+// Parameters such as charset=utf-8 or the multipart boundary are fine.
+//
+// Without it Bind quietly skips a text/plain or form body and validation
+// reports "name is required", which hides the real mistake from the client.
+func RequireContentType(types ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodPost, http.MethodPut, http.MethodPatch:
+			default:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if err != nil || !slices.Contains(types, mt) {
+				writeError(w, r, http.StatusUnsupportedMediaType, "Content-Type must be "+strings.Join(types, " or "))
+				return
+			}
+
+			empty, err := emptyBody(r)
+			if err != nil {
+				writeError(w, r, http.StatusBadRequest, "could not read request body")
+				return
+			}
+			if empty {
+				writeError(w, r, http.StatusBadRequest, "request body is required")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// emptyBody reports whether r has no body. A chunked request has no
+// Content-Length, so one byte is peeked and put back in front of the body.
+func emptyBody(r *http.Request) (bool, error) {
+	if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 {
+		return true, nil
+	}
+	if r.ContentLength > 0 {
+		return false, nil
+	}
+
+	br := bufio.NewReaderSize(r.Body, 16)
+	if _, err := br.Peek(1); err == io.EOF {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{br, r.Body}
+
+	return false, nil
+}
@@ -0,0 +1,65 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/middleware"
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/testutil"
+)
+
+func TestRequireContentType(t *testing.T) {
+	mw := middleware.RequireContentType("application/json", "multipart/form-data")
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		body        string
+		chunked     bool
+		status      int
+		code        string
+	}{
+		{"json", http.MethodPost, "application/json", `{}`, false, http.StatusOK, ""},
+		{"json with charset", http.MethodPut, "application/json; charset=utf-8", `{}`, false, http.StatusOK, ""},
+		{"multipart", http.MethodPost, "multipart/form-data; boundary=x", "--x--", false, http.StatusOK, ""},
+		{"GET is not checked", http.MethodGet, "", "", false, http.StatusOK, ""},
+		{"DELETE is not checked", http.MethodDelete, "text/plain", "", false, http.StatusOK, ""},
+		{"form", http.MethodPost, "application/x-www-form-urlencoded", "a=1", false, http.StatusUnsupportedMediaType, "unsupported_media_type"},
+		{"missing type", http.MethodPatch, "", `{}`, false, http.StatusUnsupportedMediaType, "unsupported_media_type"},
+		{"malformed type", http.MethodPost, "application/json; =", `{}`, false, http.StatusUnsupportedMediaType, "unsupported_media_type"},
+		{"empty body", http.MethodPost, "application/json", "", false, http.StatusBadRequest, "bad_request"},
+		{"empty chunked body", http.MethodPost, "application/json", "", true, http.StatusBadRequest, "bad_request"},
+		{"chunked body", http.MethodPost, "application/json", `{"a":1}`, true, http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/", strings.NewReader(tt.body))
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec, got := run(mw, req)
+
+			if tt.status != http.StatusOK {
+				testutil.AssertError(t, rec, tt.status, tt.code)
+				if got != nil {
+					t.Error("handler reached")
+				}
+				return
+			}
+			testutil.AssertStatus(t, rec, http.StatusOK)
+			if got == nil {
+				t.Fatal("handler not reached")
+			}
+			// the peeked byte of a chunked body is not lost
+			if body, _ := io.ReadAll(got.Body); string(body) != tt.body {
+				t.Errorf("handler read %q, want %q", body, tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+// Package middleware holds the request plumbing several examples need:
+// request IDs, bearer and API-key auth, tiered rate limiting,
+// Content-Type enforcement and tenant resolution.
+//
+// Every middleware is a plain func(http.Handler) http.Handler, so one copy
+// serves Echo v4 and v5 alike through echo.WrapMiddleware:
+//
+//	e.Use(echo.WrapMiddleware(middleware.RequestID))
+//	e.Use(echo.WrapMiddleware(middleware.RequireContentType(echo.MIMEApplicationJSON)))
+//
+// Rejections are written directly, in the pkg/response error envelope, so
+// they look the same as errors from the app's own error handler.
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/response"
+)
+
+// writeError answers r with the shared error envelope. The code is derived
+// from the status, as in response.Error.
+func writeError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	if r.Method == http.MethodHead {
+		w.WriteHeader(status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response.ErrorResponse{Error: response.ErrorBody{
+		Code:    response.CodeFor(status),
+		Message: message,
+	}})
+}
@@ -0,0 +1,33 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/middleware"
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/testutil"
+)
+
+// run sends req through mw in front of a handler that answers 200 "ok",
+// and reports whether that handler was reached and with which request.
+func run(mw func(http.Handler) http.Handler, req *http.Request) (rec *httptest.ResponseRecorder, got *http.Request) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		_, _ = w.Write([]byte("ok"))
+	})
+	return testutil.Serve(mw(next), req), got
+}
+
+func TestRejectionOnHEAD(t *testing.T) {
+	mw := middleware.APIKey("X-API-Key", middleware.Keys("k"))
+	rec, got := run(mw, httptest.NewRequest(http.MethodHead, "/", nil))
+
+	testutil.AssertStatus(t, rec, http.StatusUnauthorized)
+	if got != nil {
+		t.Error("handler reached")
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("HEAD response has a body: %s", rec.Body)
+	}
+}
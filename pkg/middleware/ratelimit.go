@@ -0,0 +1,213 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// idleTimeout is how long a client's limiter is kept after its last
+// request. A returning client starts with a full bucket, which is what it
+// would have had by then anyway.
+const idleTimeout = 10 * time.Minute
+
+// KeyFunc picks the bucket a request counts against.
+type KeyFunc func(r *http.Request) string
+
+// ByIP keys by the connection's remote address. Behind a proxy every
+// request comes from the proxy; key on a trusted forwarded header instead.
+func ByIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// RateLimit allows each key perSecond requests on average with bursts of
+// up to burst, and answers 429 with Retry-After beyond that. Limits are
+// kept in memory, so with several instances each one counts on its own.
+func RateLimit(perSecond float64, burst int, key KeyFunc) func(http.Handler) http.Handler {
+	classify := func(r *http.Request) (string, string) { return "", key(r) }
+	return NewLimiter(classify, Tier{PerSecond: perSecond, Burst: burst}).Limit(1)
+}
+
+// Tier is a class of client with its own limits, such as anonymous
+// visitors, free accounts and paid API keys. Each client in a tier gets
+// PerSecond and Burst; TotalPerSecond, when set, also caps the tier as a
+// whole, so many anonymous addresses together cannot crowd out everyone
+// else.
+type Tier struct {
+	Name      string
+	PerSecond float64
+	Burst     int
+
+	TotalPerSecond float64
+	TotalBurst     int
+}
+
+// Classifier names the tier a request belongs to and the client it counts
+// against within that tier.
+type Classifier func(r *http.Request) (tier, key string)
+
+// ByCredential classifies by the bearer token, or else the API key in
+// header: plan returns the tier a credential's plan is on, or "" for an
+// unknown credential. Requests without a known credential are in the
+// anonymous tier, keyed by IP. Classification runs before auth, so plan
+// must only return a tier for valid credentials.
+func ByCredential(header string, plan func(credential string) string, anonymous string) Classifier {
+	return func(r *http.Request) (string, string) {
+		cred, ok := bearerToken(r)
+		if !ok {
+			cred = r.Header.Get(header)
+		}
+		if cred != "" {
+			if tier := plan(cred); tier != "" {
+				// key on a hash, so the limiter holds no credentials
+				sum := sha256.Sum256([]byte(cred))
+				return tier, hex.EncodeToString(sum[:8])
+			}
+		}
+		return anonymous, ByIP(r)
+	}
+}
+
+// Limiter enforces per-tier limits. Build one per API and put Limit on
+// every route, so all routes draw from the same buckets:
+//
+//	limits := middleware.NewLimiter(middleware.ByCredential("X-API-Key", plans, "anonymous"),
+//		middleware.Tier{Name: "anonymous", PerSecond: 1, Burst: 5, TotalPerSecond: 50, TotalBurst: 100},
+//		middleware.Tier{Name: "free", PerSecond: 5, Burst: 20},
+//		middleware.Tier{Name: "pro", PerSecond: 50, Burst: 100},
+//	)
+//	e.Use(echo.WrapMiddleware(limits.Limit(1)))
+//	e.GET("/share/:token", share, echo.WrapMiddleware(limits.Limit(5)))
+type Limiter struct {
+	classify Classifier
+	tiers    map[string]*tierLimits
+	fallback *tierLimits
+}
+
+type tierLimits struct {
+	clients *limiters
+	total   *rate.Limiter // nil without TotalPerSecond
+}
+
+// NewLimiter returns a Limiter for tiers. A request classified into a tier
+// that is not listed counts against the first one. It panics without
+// tiers, since there would be no limits to apply.
+func NewLimiter(classify Classifier, tiers ...Tier) *Limiter {
+	if len(tiers) == 0 {
+		panic("middleware: NewLimiter needs at least one tier")
+	}
+	l := &Limiter{classify: classify, tiers: map[string]*tierLimits{}}
+	for _, t := range tiers {
+		tl := &tierLimits{clients: &limiters{limit: rate.Limit(t.PerSecond), burst: t.Burst, clients: map[string]*client{}}}
+		if t.TotalPerSecond > 0 {
+			tl.total = rate.NewLimiter(rate.Limit(t.TotalPerSecond), t.TotalBurst)
+		}
+		l.tiers[t.Name] = tl
+		if l.fallback == nil {
+			l.fallback = tl
+		}
+	}
+	return l
+}
+
+// Limit charges each request cost tokens, so expensive routes use up a
+// client's allowance faster than cheap ones. A cost above a tier's burst
+// can never be paid and is always refused.
+func (l *Limiter) Limit(cost int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if d, ok := l.reserve(r, cost); !ok || d > 0 {
+				if ok {
+					w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
+				}
+				writeError(w, r, http.StatusTooManyRequests, "rate limit exceeded")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// reserve takes cost tokens from the client's bucket and the tier's, or
+// from neither: a refused request gives its tokens back, since it is
+// refused, not queued. It returns how long until both could pay.
+func (l *Limiter) reserve(r *http.Request, cost int) (time.Duration, bool) {
+	name, key := l.classify(r)
+	tl, ok := l.tiers[name]
+	if !ok {
+		tl = l.fallback
+	}
+
+	now := time.Now()
+	res := tl.clients.reserve(key, now, cost)
+	if !res.OK() {
+		return 0, false
+	}
+	d := res.DelayFrom(now)
+
+	var total *rate.Reservation
+	if tl.total != nil {
+		if total = tl.total.ReserveN(now, cost); !total.OK() {
+			res.CancelAt(now)
+			return 0, false
+		}
+		d = max(d, total.DelayFrom(now))
+	}
+
+	if d > 0 {
+		res.CancelAt(now)
+		if total != nil {
+			total.CancelAt(now)
+		}
+	}
+	return d, true
+}
+
+type client struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+type limiters struct {
+	limit rate.Limit
+	burst int
+
+	mu        sync.Mutex
+	clients   map[string]*client
+	lastSweep time.Time
+}
+
+func (l *limiters) reserve(key string, now time.Time, n int) *rate.Reservation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// drop idle clients now and then, so the map does not grow with every
+	// address that ever sent a request
+	if now.Sub(l.lastSweep) > idleTimeout {
+		for k, c := range l.clients {
+			if now.Sub(c.lastSeen) > idleTimeout {
+				delete(l.clients, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	c, ok := l.clients[key]
+	if !ok {
+		c = &client{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.clients[key] = c
+	}
+	c.lastSeen = now
+	return c.limiter.ReserveN(now, n)
+}
@@ -0,0 +1,162 @@
+package middleware_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/middleware"
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/testutil"
+)
+
+// slow refills so rarely that no token comes back during a test.
+const slow = 0.001
+
+// byHeaders classifies by X-Tier and keys by X-Client.
+func byHeaders(r *http.Request) (string, string) {
+	return r.Header.Get("X-Tier"), r.Header.Get("X-Client")
+}
+
+// send runs one request for tier and client through mw.
+func send(t *testing.T, mw func(http.Handler) http.Handler, tier, client string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Tier", tier)
+	req.Header.Set("X-Client", client)
+	rec, _ := run(mw, req)
+	return rec
+}
+
+// allowed counts how many of n requests got through.
+func allowed(t *testing.T, mw func(http.Handler) http.Handler, tier, client string, n int) int {
+	t.Helper()
+	ok := 0
+	for range n {
+		if send(t, mw, tier, client).Code == http.StatusOK {
+			ok++
+		}
+	}
+	return ok
+}
+
+func TestNewLimiterWithoutTiers(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewLimiter without tiers did not panic")
+		}
+	}()
+	middleware.NewLimiter(byHeaders)
+}
+
+func TestLimiterTiers(t *testing.T) {
+	limits := middleware.NewLimiter(byHeaders,
+		middleware.Tier{Name: "anonymous", PerSecond: slow, Burst: 1},
+		middleware.Tier{Name: "pro", PerSecond: slow, Burst: 3},
+	)
+	mw := limits.Limit(1)
+
+	if got := allowed(t, mw, "pro", "a", 5); got != 3 {
+		t.Errorf("pro client got %d requests, want its burst of 3", got)
+	}
+	if got := allowed(t, mw, "anonymous", "a", 5); got != 1 {
+		t.Errorf("anonymous client got %d requests, want 1; tiers share buckets?", got)
+	}
+	if got := allowed(t, mw, "pro", "b", 5); got != 3 {
+		t.Errorf("second pro client got %d requests, want its own 3", got)
+	}
+	// an unlisted tier counts against the first one
+	if got := allowed(t, mw, "unknown", "c", 5); got != 1 {
+		t.Errorf("unknown tier got %d requests, want the anonymous burst of 1", got)
+	}
+
+	rec := send(t, mw, "pro", "a")
+	testutil.AssertError(t, rec, http.StatusTooManyRequests, "too_many_requests")
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("429 without Retry-After")
+	}
+}
+
+func TestLimiterCost(t *testing.T) {
+	limits := middleware.NewLimiter(byHeaders, middleware.Tier{PerSecond: slow, Burst: 5})
+	cheap, expensive := limits.Limit(1), limits.Limit(2)
+
+	if got := allowed(t, expensive, "", "a", 3); got != 2 {
+		t.Fatalf("cost 2 with burst 5: %d allowed, want 2", got)
+	}
+	// the refused third one gave its tokens back, so one is left
+	if got := allowed(t, cheap, "", "a", 3); got != 1 {
+		t.Errorf("cost 1 afterwards: %d allowed, want 1", got)
+	}
+}
+
+func TestLimiterCostAboveBurst(t *testing.T) {
+	limits := middleware.NewLimiter(byHeaders, middleware.Tier{PerSecond: 1000, Burst: 2})
+
+	for range 3 {
+		rec := send(t, limits.Limit(3), "", "a")
+		testutil.AssertStatus(t, rec, http.StatusTooManyRequests)
+		// waiting would not help, so there is no Retry-After
+		if ra := rec.Header().Get("Retry-After"); ra != "" {
+			t.Errorf("Retry-After = %q for a cost that can never be paid", ra)
+		}
+	}
+	// and the refusals took nothing
+	if got := allowed(t, limits.Limit(1), "", "a", 2); got != 2 {
+		t.Errorf("%d allowed after the refusals, want the full burst of 2", got)
+	}
+}
+
+func TestLimiterTotalReturnsTokens(t *testing.T) {
+	t.Run("client refusal does not charge the total", func(t *testing.T) {
+		limits := middleware.NewLimiter(byHeaders,
+			middleware.Tier{PerSecond: slow, Burst: 1, TotalPerSecond: slow, TotalBurst: 2})
+		mw := limits.Limit(1)
+
+		if got := allowed(t, mw, "", "a", 4); got != 1 {
+			t.Fatalf("client a: %d allowed, want 1", got)
+		}
+		// a's three refusals must not have used the tier's second token
+		if got := allowed(t, mw, "", "b", 1); got != 1 {
+			t.Errorf("client b refused; refused requests used up the total")
+		}
+		if got := allowed(t, mw, "", "c", 1); got != 0 {
+			t.Errorf("client c allowed past the total of 2")
+		}
+	})
+
+	t.Run("total refusal gives the client its tokens back", func(t *testing.T) {
+		// the total refills every 10ms; the client never does
+		limits := middleware.NewLimiter(byHeaders,
+			middleware.Tier{PerSecond: slow, Burst: 2, TotalPerSecond: 100, TotalBurst: 1})
+		mw := limits.Limit(1)
+
+		testutil.AssertStatus(t, send(t, mw, "", "a"), http.StatusOK)
+		rec := send(t, mw, "", "a")
+		testutil.AssertStatus(t, rec, http.StatusTooManyRequests)
+		testutil.AssertHeader(t, rec, "Retry-After", "1")
+
+		time.Sleep(30 * time.Millisecond)
+		// the client's second token is still there
+		testutil.AssertStatus(t, send(t, mw, "", "a"), http.StatusOK)
+		time.Sleep(30 * time.Millisecond)
+		testutil.AssertStatus(t, send(t, mw, "", "a"), http.StatusTooManyRequests)
+	})
+}
+
+func TestRateLimit(t *testing.T) {
+	mw := middleware.RateLimit(slow, 2, middleware.ByIP)
+	req := func(addr string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = addr
+		return r
+	}
+	// the port differs per connection; the limit is per address
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		rec, _ := run(mw, req(fmt.Sprintf("192.0.2.1:%d", 50000+i)))
+		testutil.AssertStatus(t, rec, want)
+	}
+	rec, _ := run(mw, req("192.0.2.2:1000"))
+	testutil.AssertStatus(t, rec, http.StatusOK)
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/idgen"
+)
+
+// RequestIDHeader carries the request ID in both directions.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLen bounds an incoming ID, which ends up in every log line.
+const maxRequestIDLen = 128
+
+type requestIDKey struct{}
+
+// RequestID gives every request an ID: the caller's X-Request-ID when it
+// is sane, otherwise a new UUIDv7. The ID is echoed in the response header
+// and stored in the request context for RequestIDFrom.
+//
+// Keeping the caller's ID lets one request be followed through a proxy or
+// another service that already assigned it.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = idgen.New()
+			r.Header.Set(RequestIDHeader, id)
+		}
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), id)))
+	})
+}
+
+// WithRequestID returns ctx carrying id, for work that starts outside an
+// HTTP request (a job, a consumer) but should log like one.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFrom returns the request ID in ctx, or "" without one.
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// validRequestID accepts printable ASCII up to maxRequestIDLen, so a client
+// cannot inject newlines into logs or send a megabyte of ID.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/middleware"
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/testutil"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{"none", "", false},
+		{"kept", "abc-123", true},
+		{"max length", strings.Repeat("a", 128), true},
+		{"too long", strings.Repeat("a", 129), false},
+		{"space", "abc 123", false},
+		{"non-ASCII", "abc-é", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.incoming != "" {
+				req.Header.Set(middleware.RequestIDHeader, tt.incoming)
+			}
+			rec, got := run(middleware.RequestID, req)
+			testutil.AssertStatus(t, rec, http.StatusOK)
+
+			id := rec.Header().Get(middleware.RequestIDHeader)
+			if tt.keep && id != tt.incoming {
+				t.Errorf("ID = %q, want the incoming %q", id, tt.incoming)
+			}
+			if !tt.keep && (id == "" || id == tt.incoming) {
+				t.Errorf("ID = %q, want a new one", id)
+			}
+			if from := middleware.RequestIDFrom(got.Context()); from != id {
+				t.Errorf("RequestIDFrom = %q, want %q", from, id)
+			}
+			// handlers reading the header see the same ID
+			if h := got.Header.Get(middleware.RequestIDHeader); h != id {
+				t.Errorf("request header = %q, want %q", h, id)
+			}
+		})
+	}
+}
+
+func TestWithRequestID(t *testing.T) {
+	ctx := middleware.WithRequestID(context.Background(), "job-7")
+	if got := middleware.RequestIDFrom(ctx); got != "job-7" {
+		t.Errorf("RequestIDFrom = %q", got)
+	}
+	if got := middleware.RequestIDFrom(context.Background()); got != "" {
+		t.Errorf("RequestIDFrom without ID = %q", got)
+	}
+}
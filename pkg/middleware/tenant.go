@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+type tenantKey struct{}
+
+// TenantResolver finds the tenant a request is for, or "" when it names
+// none.
+type TenantResolver func(r *http.Request) string
+
+// TenantHeader reads the tenant from a header such as X-Tenant-ID, which
+// suits service-to-service calls.
+func TenantHeader(name string) TenantResolver {
+	return func(r *http.Request) string {
+		return strings.TrimSpace(r.Header.Get(name))
+	}
+}
+
+// TenantSubdomain reads the tenant from the first label of the host under
+// domain: acme.example.com is tenant "acme" for domain "example.com".
+func TenantSubdomain(domain string) TenantResolver {
+	suffix := "." + strings.ToLower(domain)
+	return func(r *http.Request) string {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		sub, ok := strings.CutSuffix(strings.ToLower(host), suffix)
+		if !ok || sub == "" || strings.Contains(sub, ".") {
+			return ""
+		}
+		return sub
+	}
+}
+
+// Tenant resolves the tenant of every request and stores it for TenantFrom.
+// A request naming no tenant gets 400. When known is set, a tenant it does
+// not recognise gets 404.
+func Tenant(resolve TenantResolver, known func(id string) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := resolve(r)
+			if id == "" {
+				writeError(w, r, http.StatusBadRequest, "tenant is required")
+				return
+			}
+			if known != nil && !known(id) {
+				writeError(w, r, http.StatusNotFound, "unknown tenant")
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), tenantKey{}, id)))
+		})
+	}
+}
+
+// TenantFrom returns the tenant stored by Tenant, or "" outside it.
+func TenantFrom(ctx context.Context) string {
+	id, _ := ctx.Value(tenantKey{}).(string)
+	return id
+}
@@ -0,0 +1,90 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/middleware"
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/testutil"
+)
+
+func TestTenantHeader(t *testing.T) {
+	resolve := middleware.TenantHeader("X-Tenant-ID")
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if got := resolve(req); got != "" {
+		t.Errorf("no header: %q", got)
+	}
+	req.Header.Set("X-Tenant-ID", "  acme ")
+	if got := resolve(req); got != "acme" {
+		t.Errorf("got %q, want acme", got)
+	}
+}
+
+func TestTenantSubdomain(t *testing.T) {
+	resolve := middleware.TenantSubdomain("Example.com")
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"acme.example.com", "acme"},
+		{"ACME.Example.COM", "acme"},
+		{"acme.example.com:8080", "acme"},
+		{"example.com", ""},
+		{"a.b.example.com", ""},
+		{"acme.example.org", ""},
+		{"acmeexample.com", ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Host = tt.host
+		if got := resolve(req); got != tt.want {
+			t.Errorf("host %q: got %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestTenant(t *testing.T) {
+	known := func(id string) bool { return id == "acme" }
+	tests := []struct {
+		name   string
+		tenant string
+		known  func(string) bool
+		status int
+		code   string
+	}{
+		{"known", "acme", known, http.StatusOK, ""},
+		{"any tenant without known", "globex", nil, http.StatusOK, ""},
+		{"missing", "", known, http.StatusBadRequest, "bad_request"},
+		{"unknown", "globex", known, http.StatusNotFound, "not_found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.tenant != "" {
+				req.Header.Set("X-Tenant-ID", tt.tenant)
+			}
+			rec, got := run(middleware.Tenant(middleware.TenantHeader("X-Tenant-ID"), tt.known), req)
+
+			if tt.status != http.StatusOK {
+				testutil.AssertError(t, rec, tt.status, tt.code)
+				if got != nil {
+					t.Error("handler reached")
+				}
+				return
+			}
+			testutil.AssertStatus(t, rec, http.StatusOK)
+			if got == nil {
+				t.Fatal("handler not reached")
+			}
+			if id := middleware.TenantFrom(got.Context()); id != tt.tenant {
+				t.Errorf("TenantFrom = %q, want %q", id, tt.tenant)
+			}
+		})
+	}
+
+	if id := middleware.TenantFrom(context.Background()); id != "" {
+		t.Errorf("TenantFrom outside Tenant = %q", id)
+	}
+}
@@ -0,0 +1,196 @@
+// Package pagination parses list query parameters and builds the page
+// metadata lists return.
+//
+// Two styles are supported:
+//
+//	offset  ?page=2&per_page=20  or  ?limit=20&offset=20
+//	cursor  ?limit=20&cursor=eyJpZCI6NDJ9
+//
+// Offset pages can jump anywhere and report a total, but shift when rows
+// are inserted in front of them. Cursor pages stay stable under inserts and
+// never need a COUNT(*), but can only move forward.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/response"
+)
+
+// Options are a list's limits. The zero value means 20 per page, at most
+// 100, with out-of-range values rejected.
+type Options struct {
+	DefaultLimit int
+	MaxLimit     int
+
+	// Clamp silently fits an out-of-range limit or page into range instead
+	// of failing. Rejecting shows client bugs early; clamping is kinder to
+	// hand-typed URLs.
+	Clamp bool
+}
+
+func (o Options) withDefaults() Options {
+	if o.DefaultLimit <= 0 {
+		o.DefaultLimit = 20
+	}
+	if o.MaxLimit <= 0 {
+		o.MaxLimit = 100
+	}
+	if o.DefaultLimit > o.MaxLimit {
+		o.DefaultLimit = o.MaxLimit
+	}
+	return o
+}
+
+// Params is a parsed page request. Page is 1-based and always consistent
+// with Offset and Limit; Cursor is the raw cursor, empty on the first page.
+type Params struct {
+	Page   int
+	Limit  int
+	Offset int
+	Cursor string
+}
+
+// Error is an invalid pagination parameter. Its message is written for the
+// client, e.g. "per_page must be between 1 and 100".
+type Error struct {
+	Param   string
+	Message string
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// Parse reads page, per_page, limit, offset and cursor from q. per_page is
+// an alias of limit; page is turned into an offset. A cursor cannot be
+// combined with page or offset.
+func Parse(q url.Values, opts Options) (Params, error) {
+	opts = opts.withDefaults()
+
+	limitParam := "limit"
+	if q.Has("per_page") {
+		limitParam = "per_page"
+	}
+	limit, err := intParam(q, limitParam, opts.DefaultLimit)
+	if err != nil {
+		return Params{}, err
+	}
+	if limit < 1 || limit > opts.MaxLimit {
+		if !opts.Clamp {
+			return Params{}, &Error{Param: limitParam, Message: fmt.Sprintf("%s must be between 1 and %d", limitParam, opts.MaxLimit)}
+		}
+		limit = min(max(limit, 1), opts.MaxLimit)
+	}
+
+	p := Params{Page: 1, Limit: limit, Cursor: q.Get("cursor")}
+	if p.Cursor != "" {
+		if q.Has("page") || q.Has("offset") {
+			return Params{}, &Error{Param: "cursor", Message: "cursor cannot be combined with page or offset"}
+		}
+		return p, nil
+	}
+
+	switch {
+	case q.Has("page"):
+		page, err := intParam(q, "page", 1)
+		if err != nil {
+			return Params{}, err
+		}
+		if page < 1 {
+			if !opts.Clamp {
+				return Params{}, &Error{Param: "page", Message: "page must be a positive integer"}
+			}
+			page = 1
+		}
+		// (page-1)*limit must not overflow into a negative offset
+		if maxPage := math.MaxInt/limit + 1; page > maxPage {
+			if !opts.Clamp {
+				return Params{}, &Error{Param: "page", Message: fmt.Sprintf("page must be at most %d", maxPage)}
+			}
+			page = maxPage
+		}
+		p.Page, p.Offset = page, (page-1)*limit
+	case q.Has("offset"):
+		offset, err := intParam(q, "offset", 0)
+		if err != nil {
+			return Params{}, err
+		}
+		if offset < 0 {
+			if !opts.Clamp {
+				return Params{}, &Error{Param: "offset", Message: "offset must not be negative"}
+			}
+			offset = 0
+		}
+		p.Page, p.Offset = offset/limit+1, offset
+	}
+	return p, nil
+}
+
+func intParam(q url.Values, name string, defaultValue int) (int, error) {
+	v := q.Get(name)
+	if v == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, &Error{Param: name, Message: name + " must be an integer"}
+	}
+	return n, nil
+}
+
+// Meta builds the offset page metadata for a list of total items.
+func (p Params) Meta(total int) response.Meta {
+	return response.NewMeta(p.Page, p.Limit, total)
+}
+
+// CursorMeta is the page metadata of a cursor list. NextCursor is empty on
+// the last page.
+type CursorMeta struct {
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// NewCursorMeta builds CursorMeta; next is the cursor of the following
+// page, or "" when this is the last one.
+func NewCursorMeta(limit int, next string) CursorMeta {
+	return CursorMeta{Limit: limit, NextCursor: next, HasMore: next != ""}
+}
+
+// Trim cuts items, fetched with limit+1, down to limit and reports whether
+// there was more. Fetching one extra row tells whether a next page exists
+// without counting.
+func Trim[T any](items []T, limit int) ([]T, bool) {
+	if len(items) > limit {
+		return items[:limit], true
+	}
+	return items, false
+}
+
+// EncodeCursor turns the position of the last item, e.g.
+// struct{ID string}{"0189..."}, into an opaque URL-safe cursor. Clients
+// pass it back unchanged and should not parse it.
+func EncodeCursor(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// DecodeCursor reverses EncodeCursor. A cursor that is not one of ours is
+// an *Error, so it reaches the client as a 400.
+func DecodeCursor(cursor string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		err = json.Unmarshal(b, v)
+	}
+	if err != nil {
+		return &Error{Param: "cursor", Message: "invalid cursor"}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package pagination
+
+import (
+	"errors"
+	"math"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	maxInt := strconv.Itoa(math.MaxInt)
+	tests := []struct {
+		name    string
+		query   string
+		opts    Options
+		want    Params
+		wantErr string // the rejected param, empty when Parse succeeds
+	}{
+		{name: "defaults", query: "", want: Params{Page: 1, Limit: 20}},
+		{name: "page", query: "page=3&per_page=10", want: Params{Page: 3, Limit: 10, Offset: 20}},
+		{name: "offset", query: "limit=10&offset=25", want: Params{Page: 3, Limit: 10, Offset: 25}},
+		{name: "cursor", query: "limit=5&cursor=abc", want: Params{Page: 1, Limit: 5, Cursor: "abc"}},
+		{name: "limit too large", query: "per_page=101", wantErr: "per_page"},
+		{name: "limit clamped", query: "per_page=101", opts: Options{Clamp: true}, want: Params{Page: 1, Limit: 100}},
+		{name: "page zero", query: "page=0", wantErr: "page"},
+		{name: "negative offset", query: "offset=-1", wantErr: "offset"},
+		{name: "cursor with page", query: "cursor=abc&page=2", wantErr: "cursor"},
+		{name: "not an integer", query: "page=two", wantErr: "page"},
+		{name: "page overflows offset", query: "page=" + maxInt + "&per_page=20", wantErr: "page"},
+		{
+			name:  "page overflow clamped",
+			query: "page=" + maxInt + "&per_page=20",
+			opts:  Options{Clamp: true},
+			want:  Params{Page: math.MaxInt/20 + 1, Limit: 20, Offset: math.MaxInt / 20 * 20},
+		},
+		{name: "largest page", query: "page=" + strconv.Itoa(math.MaxInt/20+1) + "&per_page=20", want: Params{Page: math.MaxInt/20 + 1, Limit: 20, Offset: math.MaxInt / 20 * 20}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Parse(q, tt.opts)
+			if tt.wantErr != "" {
+				var pe *Error
+				if !errors.As(err, &pe) || pe.Param != tt.wantErr {
+					t.Fatalf("Parse(%q) error = %v, want an *Error for %s", tt.query, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.query, err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.query, got, tt.want)
+			}
+			if got.Offset < 0 {
+				t.Errorf("Parse(%q) offset %d is negative", tt.query, got.Offset)
+			}
+		})
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	type pos struct{ ID string }
+	c, err := EncodeCursor(pos{ID: "0189"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got pos
+	if err := DecodeCursor(c, &got); err != nil || got.ID != "0189" {
+		t.Fatalf("DecodeCursor(%q) = %+v, %v", c, got, err)
+	}
+	var pe *Error
+	if err := DecodeCursor("not-a-cursor!", &got); !errors.As(err, &pe) {
+		t.Fatalf("DecodeCursor(garbage) error = %v, want *Error", err)
+	}
+}
+
+func TestTrim(t *testing.T) {
+	items, more := Trim([]int{1, 2, 3}, 2)
+	if len(items) != 2 || !more {
+		t.Errorf("Trim(3 items, 2) = %v, %v", items, more)
+	}
+	items, more = Trim([]int{1, 2}, 2)
+	if len(items) != 2 || more {
+		t.Errorf("Trim(2 items, 2) = %v, %v", items, more)
+	}
+}
@@ -0,0 +1,139 @@
+// Package position generates sort keys for manually ordered lists, such as
+// todos a user drags into place. A key is a string of base-62 digits read
+// as a fraction, so there is always room for another key between two:
+//
+//	first, _ := position.Between("", "")    // "V"
+//	last, _ := position.Between(first, "")  // "l"
+//	mid, _ := position.Between(first, last) // "d"
+//
+// Moving an item writes one row, its new key, instead of renumbering every
+// item after it. Keys grow by a character every few inserts at the same
+// spot; when one gets longer than MaxLen, rewrite the whole list with Spread
+// in one batch.
+//
+// Keys compare byte-wise, so a Postgres column needs COLLATE "C":
+//
+//	position text COLLATE "C" NOT NULL
+package position
+
+import (
+	"errors"
+	"strings"
+)
+
+// digits are in ASCII order, so comparing keys as strings compares them as
+// fractions.
+const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// MaxLen is the key length after which a list is worth rewriting with
+// Spread. Inserting always works; this only keeps keys short.
+const MaxLen = 16
+
+var (
+	ErrInvalidKey = errors.New("position: invalid key")
+	ErrOrder      = errors.New("position: lower key is not below upper key")
+)
+
+// Valid reports whether key is a key this package could have made: base-62
+// digits, not empty and not ending in "0".
+func Valid(key string) bool {
+	if key == "" || key[len(key)-1] == '0' {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(digits, key[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Between returns a key that sorts after lower and before upper. An empty
+// lower means the start of the list and an empty upper its end, so
+// Between("", first) prepends and Between(last, "") appends.
+func Between(lower, upper string) (string, error) {
+	if (lower != "" && !Valid(lower)) || (upper != "" && !Valid(upper)) {
+		return "", ErrInvalidKey
+	}
+	if upper != "" && lower >= upper {
+		return "", ErrOrder
+	}
+	return midpoint(lower, upper), nil
+}
+
+// midpoint works digit by digit: skip the prefix both keys share, then take
+// a digit halfway between theirs, or, when the digits are adjacent, keep
+// lower's digit and recurse with no upper bound.
+func midpoint(a, b string) string {
+	if b != "" {
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + midpoint(rest, b[n:])
+		}
+	}
+
+	da := 0
+	if a != "" {
+		da = strings.IndexByte(digits, a[0])
+	}
+	db := len(digits)
+	if b != "" {
+		db = strings.IndexByte(digits, b[0])
+	}
+
+	if db-da > 1 {
+		return string(digits[(da+db+1)/2])
+	}
+	// b's first digit alone sorts between a and b when b continues
+	if len(b) > 1 {
+		return b[:1]
+	}
+	rest := ""
+	if len(a) > 1 {
+		rest = a[1:]
+	}
+	return string(digits[da]) + midpoint(rest, "")
+}
+
+// digitAt is key's i-th digit, with keys padded by "0" on the right.
+func digitAt(key string, i int) byte {
+	if i < len(key) {
+		return key[i]
+	}
+	return '0'
+}
+
+// Spread returns n evenly spaced keys in ascending order, as short as n
+// allows, for rewriting a whole list: after a bulk import, or when a key
+// has grown past MaxLen.
+func Spread(n int) []string {
+	if n <= 0 {
+		return nil
+	}
+
+	// width digits give 62^width slots; keep at least one free between keys
+	width, slots := 1, len(digits)
+	for slots <= n {
+		width++
+		slots *= len(digits)
+	}
+
+	keys := make([]string, n)
+	buf := make([]byte, width)
+	for i := range keys {
+		v := (i + 1) * slots / (n + 1)
+		for j := width - 1; j >= 0; j-- {
+			buf[j] = digits[v%len(digits)]
+			v /= len(digits)
+		}
+		keys[i] = strings.TrimRight(string(buf), "0")
+	}
+	return keys
+}
@@ -0,0 +1,107 @@
+// Package response writes the JSON shapes shared by the examples:
+//
+//	single resource  the resource itself                      200 / 201
+//	list             {"data":[...],"meta":{"page":1,...}}     200
+//	error            {"error":{"code":"not_found","message":"..."}}
+//
+// It is written against Responder instead of an Echo context, so the same
+// helpers serve Echo v4 (echo.Context) and Echo v5 (*echo.Context).
+package response
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Responder is the part of an Echo context the helpers need. Both Echo v4
+// and v5 contexts satisfy it.
+type Responder interface {
+	Request() *http.Request
+	JSON(code int, i any) error
+	NoContent(code int) error
+}
+
+// OK writes v with status 200.
+func OK(c Responder, v any) error {
+	return c.JSON(http.StatusOK, v)
+}
+
+// Created writes the new resource v with status 201.
+func Created(c Responder, v any) error {
+	return c.JSON(http.StatusCreated, v)
+}
+
+// NoContent writes an empty 204, the answer to a successful delete.
+func NoContent(c Responder) error {
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListResponse is one page of a list.
+type ListResponse[T any] struct {
+	Data []T `json:"data"`
+	Meta any `json:"meta"`
+}
+
+// Meta describes the page returned by Paginated. Lists that echo more of
+// their query back embed it:
+//
+//	type ListMeta struct {
+//		response.Meta
+//		Sort string `json:"sort"`
+//	}
+type Meta struct {
+	Page       int `json:"page"`
+	PerPage    int `json:"per_page"`
+	Total      int `json:"total"`
+	TotalPages int `json:"total_pages"`
+}
+
+// NewMeta fills in TotalPages from total and perPage.
+func NewMeta(page, perPage, total int) Meta {
+	m := Meta{Page: page, PerPage: perPage, Total: total}
+	if perPage > 0 {
+		m.TotalPages = (total + perPage - 1) / perPage
+	}
+	return m
+}
+
+// Paginated writes data as a list with status 200. meta is a Meta or a
+// struct embedding one. A nil slice is written as [], never null.
+func Paginated[T any](c Responder, data []T, meta any) error {
+	if data == nil {
+		data = []T{}
+	}
+	return c.JSON(http.StatusOK, ListResponse[T]{Data: data, Meta: meta})
+}
+
+// ErrorResponse is the error envelope:
+//
+//	{"error":{"code":"validation_failed","message":"validation failed","fields":{"email":"is required"}}}
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+type ErrorBody struct {
+	Code    string            `json:"code"`
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+// Error writes the error envelope with status. An empty code is derived
+// from the status (see CodeFor). HEAD requests get the status without a
+// body.
+func Error(c Responder, status int, code, message string, fields map[string]string) error {
+	if c.Request().Method == http.MethodHead {
+		return c.NoContent(status)
+	}
+	if code == "" {
+		code = CodeFor(status)
+	}
+	return c.JSON(status, ErrorResponse{Error: ErrorBody{Code: code, Message: message, Fields: fields}})
+}
+
+// CodeFor turns a status into a machine-readable code: 405 becomes
+// "method_not_allowed".
+func CodeFor(status int) string {
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
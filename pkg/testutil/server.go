@@ -0,0 +1,61 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Server is h behind a real listener on 127.0.0.1, for tests that need a
+// TCP connection: timeouts, streaming, WebSockets, or an HTTP client under
+// test. It is closed when the test ends.
+type Server struct {
+	*httptest.Server
+	t testing.TB
+}
+
+func NewServer(t testing.TB, h http.Handler) *Server {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return &Server{Server: srv, t: t}
+}
+
+// Do sends a request to path with body encoded as in JSONRequest, and
+// returns the response with its body read, so the test never leaks a
+// connection.
+func (s *Server) Do(method, path string, body any) (*http.Response, []byte) {
+	s.t.Helper()
+
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			s.t.Fatalf("testutil: encoding request body: %v", err)
+		}
+		r = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, s.URL+path, r)
+	if err != nil {
+		s.t.Fatalf("testutil: building request: %v", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := s.Client().Do(req)
+	if err != nil {
+		s.t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		s.t.Fatalf("%s %s: reading body: %v", method, path, err)
+	}
+	return res, data
+}
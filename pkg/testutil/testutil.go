@@ -0,0 +1,139 @@
+// Package testutil builds requests, runs them through any http.Handler and
+// checks the responses, for _test.go files in every module. Both Echo v4's
+// and v5's *echo.Echo are http.Handlers:
+//
+//	req := testutil.JSONRequest(t, http.MethodPost, "/users", body)
+//	rec := testutil.Serve(e, req)
+//	testutil.AssertStatus(t, rec, http.StatusCreated)
+//	user := testutil.DecodeJSON[models.UserResponse](t, rec)
+//
+// q19-handler-testing's testkit builds on it with helpers that need Echo
+// v4's context. Postgres-backed repository tests use the pgtest
+// subpackage.
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/response"
+)
+
+// TB is the part of testing.TB the request and assertion helpers use;
+// *testing.T and *testing.B satisfy it.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+	Fatalf(format string, args ...any)
+}
+
+// JSONRequest builds a request with body encoded as JSON and the matching
+// Content-Type. A nil body sends none; a string or []byte is sent as is,
+// which is how malformed JSON gets into a test.
+func JSONRequest(t TB, method, target string, body any) *http.Request {
+	t.Helper()
+
+	var r io.Reader
+	switch b := body.(type) {
+	case nil:
+	case string:
+		r = bytes.NewBufferString(b)
+	case []byte:
+		r = bytes.NewBuffer(b)
+	default:
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("testutil: encoding request body: %v", err)
+		}
+		r = bytes.NewBuffer(data)
+	}
+
+	req := httptest.NewRequest(method, target, r)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req
+}
+
+// Serve sends req through h, router and middleware included, without a
+// network listener.
+func Serve(h http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// AssertStatus fails the test when the status is not want, and shows the
+// body, which usually says why.
+func AssertStatus(t TB, rec *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	if rec.Code != want {
+		t.Errorf("status = %d, want %d; body: %s", rec.Code, want, rec.Body.String())
+	}
+}
+
+// AssertHeader fails the test when header name is not want.
+func AssertHeader(t TB, rec *httptest.ResponseRecorder, name, want string) {
+	t.Helper()
+	if got := rec.Header().Get(name); got != want {
+		t.Errorf("header %s = %q, want %q", name, got, want)
+	}
+}
+
+// AssertJSON compares the body with want as JSON values, so key order and
+// whitespace do not matter. want may be a string of JSON or any value that
+// encodes to the expected document.
+func AssertJSON(t TB, rec *httptest.ResponseRecorder, want any) {
+	t.Helper()
+
+	var wantJSON []byte
+	switch w := want.(type) {
+	case string:
+		wantJSON = []byte(w)
+	case []byte:
+		wantJSON = w
+	default:
+		var err error
+		if wantJSON, err = json.Marshal(want); err != nil {
+			t.Fatalf("testutil: encoding expected JSON: %v", err)
+		}
+	}
+
+	var got, exp any
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Errorf("body is not JSON: %v; body: %s", err, rec.Body.String())
+		return
+	}
+	if err := json.Unmarshal(wantJSON, &exp); err != nil {
+		t.Fatalf("testutil: expected value is not JSON: %v", err)
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("body = %s, want %s", bytes.TrimSpace(rec.Body.Bytes()), wantJSON)
+	}
+}
+
+// AssertError checks for the shared error envelope with the given status
+// and code, and returns it so the test can look at the message or fields.
+func AssertError(t TB, rec *httptest.ResponseRecorder, status int, code string) response.ErrorBody {
+	t.Helper()
+	AssertStatus(t, rec, status)
+	body := DecodeJSON[response.ErrorResponse](t, rec).Error
+	if body.Code != code {
+		t.Errorf("error code = %q, want %q; message: %s", body.Code, code, body.Message)
+	}
+	return body
+}
+
+// DecodeJSON decodes the body into a T, failing the test if it cannot.
+func DecodeJSON[T any](t TB, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal(rec.Body.Bytes(), &v); err != nil {
+		t.Fatalf("decoding body into %T: %v; body: %s", v, err, rec.Body.String())
+	}
+	return v
+}
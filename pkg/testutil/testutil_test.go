@@ -0,0 +1,154 @@
+package testutil
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// recorder is a TB that collects failures instead of failing, so the
+// assertions themselves can be tested. Fatalf panics with fatal, which
+// check recovers, as testing's Goexit would end the test.
+type recorder struct {
+	errors []string
+	fatal  string
+}
+
+type fatal struct{}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.fatal = fmt.Sprintf(format, args...)
+	panic(fatal{})
+}
+
+// check runs fn against a fresh recorder and returns it.
+func check(fn func(tb TB)) (r *recorder) {
+	r = &recorder{}
+	defer func() {
+		if p := recover(); p != nil {
+			if _, ok := p.(fatal); !ok {
+				panic(p)
+			}
+		}
+	}()
+	fn(r)
+	return r
+}
+
+func (r *recorder) failed() bool { return len(r.errors) > 0 || r.fatal != "" }
+
+// echoJSON answers every request with status and a fixed body, echoing the
+// request's Content-Type and body in headers.
+func echoJSON(status int, body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		in, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Got-Content-Type", r.Header.Get("Content-Type"))
+		w.Header().Set("X-Got-Body", string(in))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	})
+}
+
+func TestJSONRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     any
+		wantBody string
+		wantCT   string
+	}{
+		{name: "nil", body: nil, wantBody: "", wantCT: ""},
+		{name: "struct", body: struct {
+			Name string `json:"name"`
+		}{"Ann"}, wantBody: `{"name":"Ann"}`, wantCT: "application/json"},
+		{name: "raw string", body: `{"name":`, wantBody: `{"name":`, wantCT: "application/json"},
+		{name: "bytes", body: []byte(`[]`), wantBody: `[]`, wantCT: "application/json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := JSONRequest(t, http.MethodPost, "/x", tt.body)
+			rec := Serve(echoJSON(http.StatusOK, `{}`), req)
+			AssertHeader(t, rec, "X-Got-Body", tt.wantBody)
+			AssertHeader(t, rec, "X-Got-Content-Type", tt.wantCT)
+		})
+	}
+
+	r := check(func(tb TB) { JSONRequest(tb, http.MethodPost, "/", make(chan int)) })
+	if r.fatal == "" {
+		t.Error("JSONRequest with an unencodable body did not fail")
+	}
+}
+
+func TestAssertions(t *testing.T) {
+	rec := Serve(echoJSON(http.StatusBadRequest, `{"error":{"code":"bad_request","message":"nope","fields":{"a":"b"}}}`),
+		JSONRequest(t, http.MethodGet, "/", nil))
+
+	tests := []struct {
+		name       string
+		fn         func(tb TB)
+		wantFailed bool
+	}{
+		{"status ok", func(tb TB) { AssertStatus(tb, rec, http.StatusBadRequest) }, false},
+		{"status wrong", func(tb TB) { AssertStatus(tb, rec, http.StatusOK) }, true},
+		{"header ok", func(tb TB) { AssertHeader(tb, rec, "Content-Type", "application/json") }, false},
+		{"header wrong", func(tb TB) { AssertHeader(tb, rec, "Content-Type", "text/plain") }, true},
+		{"json ignores key order and spacing", func(tb TB) {
+			AssertJSON(tb, rec, `{ "error": {"fields":{"a":"b"}, "message":"nope", "code":"bad_request"} }`)
+		}, false},
+		{"json from a value", func(tb TB) {
+			AssertJSON(tb, rec, map[string]any{"error": map[string]any{"code": "bad_request", "message": "nope", "fields": map[string]string{"a": "b"}}})
+		}, false},
+		{"json differs", func(tb TB) { AssertJSON(tb, rec, `{"error":{}}`) }, true},
+		{"json expectation invalid", func(tb TB) { AssertJSON(tb, rec, `{`) }, true},
+		{"error envelope ok", func(tb TB) { AssertError(tb, rec, http.StatusBadRequest, "bad_request") }, false},
+		{"error code wrong", func(tb TB) { AssertError(tb, rec, http.StatusBadRequest, "not_found") }, true},
+		{"decode into wrong type", func(tb TB) { DecodeJSON[[]int](tb, rec) }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := check(tt.fn)
+			if r.failed() != tt.wantFailed {
+				t.Errorf("failed = %v, want %v (errors %q, fatal %q)", r.failed(), tt.wantFailed, r.errors, r.fatal)
+			}
+		})
+	}
+
+	body := AssertError(t, rec, http.StatusBadRequest, "bad_request")
+	if body.Message != "nope" || body.Fields["a"] != "b" {
+		t.Errorf("AssertError returned %+v", body)
+	}
+
+	notJSON := Serve(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, "plain") }),
+		JSONRequest(t, http.MethodGet, "/", nil))
+	if r := check(func(tb TB) { AssertJSON(tb, notJSON, `{}`) }); len(r.errors) != 1 || !strings.Contains(r.errors[0], "not JSON") {
+		t.Errorf("AssertJSON on a plain body: errors %q", r.errors)
+	}
+}
+
+func TestServer(t *testing.T) {
+	srv := NewServer(t, echoJSON(http.StatusCreated, `{"id":1}`))
+
+	res, body := srv.Do(http.MethodPost, "/users", map[string]string{"name": "Ann"})
+	if res.StatusCode != http.StatusCreated || string(body) != `{"id":1}` {
+		t.Errorf("Do = %d %s", res.StatusCode, body)
+	}
+	if got := res.Header.Get("X-Got-Body"); got != `{"name":"Ann"}` {
+		t.Errorf("server received body %q", got)
+	}
+	if got := res.Header.Get("X-Got-Content-Type"); got != "application/json" {
+		t.Errorf("server received Content-Type %q", got)
+	}
+
+	res, _ = srv.Do(http.MethodGet, "/", nil)
+	if got := res.Header.Get("X-Got-Content-Type"); got != "" {
+		t.Errorf("GET without body sent Content-Type %q", got)
+	}
+}
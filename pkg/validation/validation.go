@@ -0,0 +1,220 @@
+// Package validation wraps go-playground/validator with the examples'
+// custom rules and turns its errors into apierror.Validation, so every
+// module reports invalid input the same way:
+//
+//	{"error":{"code":"validation_failed","message":"validation failed","fields":{"email":"is required"}}}
+//
+// A *Validator satisfies both Echo v4's and v5's echo.Validator:
+//
+//	e.Validator = validation.New()
+//	...
+//	if err := c.Validate(&req); err != nil {
+//		return err // already an *apierror.Error
+//	}
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/apierror"
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/clock"
+)
+
+// Enum is implemented by string types with a fixed set of values, such as
+// a status. The enum rule checks a field against Values().
+type Enum interface {
+	Values() []string
+}
+
+type Validator struct {
+	validate *validator.Validate
+	clock    clock.Clock
+}
+
+// Option customises a Validator built with New.
+type Option func(*Validator)
+
+// WithClock sets the clock future_date compares against, so tests can fix
+// "now". The default is clock.Real{}.
+func WithClock(c clock.Clock) Option {
+	return func(v *Validator) { v.clock = c }
+}
+
+// New returns a Validator that reports fields by their JSON names and
+// knows these rules besides the built-in ones:
+//
+//	strong_password  at least three of: lower case, upper case, digit, symbol
+//	max_bytes        a string of at most the given number of bytes, where
+//	                 max counts characters: max_bytes=72 for bcrypt
+//	future_date      a time.Time after now
+//	enum             a value of the field's Enum type, or of the listed
+//	                 values: enum=low medium high
+func New(opts ...Option) *Validator {
+	val := &Validator{clock: clock.Real{}}
+	for _, opt := range opts {
+		opt(val)
+	}
+
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// report fields by their JSON name ("email"), not the Go name ("Email")
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	// the rules are fixed and valid, so registering them cannot fail
+	_ = v.RegisterValidation("strong_password", strongPassword)
+	_ = v.RegisterValidation("max_bytes", maxBytes)
+	_ = v.RegisterValidation("future_date", val.futureDate)
+	_ = v.RegisterValidation("enum", enum)
+
+	val.validate = v
+	return val
+}
+
+// Validate checks i's validate tags. Invalid input comes back as
+// apierror.Validation listing every failing field; any other error (i
+// is not a struct) is returned as is.
+func (v *Validator) Validate(i any) error {
+	err := v.validate.Struct(i)
+	if fields := Fields(err); fields != nil {
+		return apierror.Validation(fields)
+	}
+	return err
+}
+
+// Fields turns validator errors into {"field": "message"}, keyed by the
+// JSON path of the field ("address.city", "tags[1]"). It returns nil if
+// err is not a validation error.
+func Fields(err error) map[string]string {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return nil
+	}
+
+	fields := make(map[string]string, len(verrs))
+	for _, fe := range verrs {
+		fields[fieldPath(fe)] = message(fe)
+	}
+	return fields
+}
+
+// fieldPath drops the Go struct name the namespace starts with.
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if _, rest, ok := strings.Cut(ns, "."); ok {
+		return rest
+	}
+	return ns
+}
+
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "uuid":
+		return "must be a valid UUID"
+	case "min", "max", "len":
+		return sizeMessage(fe)
+	case "gte":
+		return "must be at least " + fe.Param()
+	case "lte":
+		return "must be at most " + fe.Param()
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(fe.Param()), ", ")
+	case "enum":
+		return "must be one of " + strings.Join(enumValues(fe.Value(), fe.Param()), ", ")
+	case "strong_password":
+		return "must mix at least three of lower case, upper case, digits and symbols"
+	case "future_date":
+		return "must be in the future"
+	case "max_bytes":
+		return "must be at most " + fe.Param() + " bytes"
+	default:
+		return fmt.Sprintf("failed %q validation", fe.Tag())
+	}
+}
+
+// sizeMessage words min, max and len by what they measure: characters for
+// strings, items for slices and maps, the value itself for numbers.
+func sizeMessage(fe validator.FieldError) string {
+	bound := map[string]string{"min": "at least ", "max": "at most ", "len": "exactly "}[fe.Tag()]
+	switch fe.Kind() {
+	case reflect.String:
+		return "must be " + bound + fe.Param() + " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "must have " + bound + fe.Param() + " items"
+	default:
+		return "must be " + bound + fe.Param()
+	}
+}
+
+func strongPassword(fl validator.FieldLevel) bool {
+	var lower, upper, digit, symbol bool
+	for _, r := range fl.Field().String() {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+
+	classes := 0
+	for _, ok := range []bool{lower, upper, digit, symbol} {
+		if ok {
+			classes++
+		}
+	}
+	return classes >= 3
+}
+
+func maxBytes(fl validator.FieldLevel) bool {
+	n, err := strconv.Atoi(fl.Param())
+	return err == nil && fl.Field().Kind() == reflect.String && len(fl.Field().String()) <= n
+}
+
+func (v *Validator) futureDate(fl validator.FieldLevel) bool {
+	t, ok := fl.Field().Interface().(time.Time)
+	return ok && t.After(v.clock.Now())
+}
+
+func enum(fl validator.FieldLevel) bool {
+	f := fl.Field()
+	if f.Kind() != reflect.String {
+		return false
+	}
+	return slices.Contains(enumValues(f.Interface(), fl.Param()), f.String())
+}
+
+// enumValues is the allowed values: the rule's parameter when given,
+// otherwise the field type's Values().
+func enumValues(v any, param string) []string {
+	if param != "" {
+		return strings.Fields(param)
+	}
+	if e, ok := v.(Enum); ok {
+		return e.Values()
+	}
+	return nil
+}
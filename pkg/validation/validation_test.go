@@ -0,0 +1,47 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/apierror"
+)
+
+func TestMaxBytes(t *testing.T) {
+	type req struct {
+		Password string `json:"password" validate:"max_bytes=8"`
+	}
+	v := New()
+
+	tests := []struct {
+		password string
+		ok       bool
+	}{
+		{"", true},
+		{"12345678", true},
+		{"123456789", false},
+		{"éééé", true},   // 4 characters, 8 bytes
+		{"ééééé", false}, // 5 characters, 10 bytes
+	}
+	for _, tt := range tests {
+		err := v.Validate(req{Password: tt.password})
+		if tt.ok {
+			if err != nil {
+				t.Errorf("%q: %v", tt.password, err)
+			}
+			continue
+		}
+		e, isAPI := err.(*apierror.Error)
+		if !isAPI || e.Fields["password"] != "must be at most 8 bytes" {
+			t.Errorf("%q: err = %v, want a password field error", tt.password, err)
+		}
+	}
+
+	// a bound of 72 bytes is what bcrypt accepts, whatever max says
+	long := strings.Repeat("é", 40)
+	if err := v.Validate(struct {
+		P string `json:"p" validate:"max=72,max_bytes=72"`
+	}{long}); err == nil {
+		t.Errorf("80-byte password passed max_bytes=72")
+	}
+}
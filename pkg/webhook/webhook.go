@@ -0,0 +1,222 @@
+// Package webhook signs outgoing webhook deliveries and verifies incoming
+// ones, so a receiver can tell a delivery came from us and was not
+// recorded and sent again. Every delivery carries
+//
+//	Webhook-Id: 0189c4f0-...                     unique per event, kept across retries
+//	Webhook-Signature: t=1791936000,v1=5257a8...  HMAC-SHA256 with the endpoint's secret
+//
+// where v1 is the hex HMAC of "<id>.<t>.<body>". Signing the ID and the
+// timestamp with the body means neither can be swapped. The receiver
+// rejects a timestamp outside Tolerance, and treats an ID it has already
+// processed as a duplicate, which together stop replays.
+//
+// An ID only counts as processed once the receiver says so with
+// MarkProcessed, after handling the delivery succeeded. A delivery that
+// failed on the receiver's side can then be retried under the same ID, and
+// a duplicate is acknowledged with 2xx so the sender stops retrying:
+//
+//	v := webhook.NewVerifier(secret)
+//	e.POST("/hooks/todos", func(c echo.Context) error {
+//		body, err := v.Verify(c.Request())
+//		switch {
+//		case errors.Is(err, webhook.ErrDuplicate):
+//			return c.NoContent(http.StatusOK) // handled before
+//		case err != nil:
+//			return apierror.Unauthorized(err.Error())
+//		}
+//		if err := handle(body); err != nil {
+//			return err // 5xx: the sender retries with the same Webhook-Id
+//		}
+//		v.MarkProcessed(c.Request().Header.Get(webhook.IDHeader))
+//		return c.NoContent(http.StatusNoContent)
+//	})
+//
+// Senders build requests with NewRequest and send them through a
+// pkg/httpclient client: the Idempotency-Key it sets lets that client
+// retry the POST.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/clock"
+)
+
+const (
+	IDHeader        = "Webhook-Id"
+	SignatureHeader = "Webhook-Signature"
+)
+
+// DefaultTolerance is how far a delivery's timestamp may be from the
+// receiver's clock. It bounds both clock skew and how long IDs are kept.
+const DefaultTolerance = 5 * time.Minute
+
+// MaxBodySize is the largest body Verify reads.
+const MaxBodySize = 1 << 20
+
+var (
+	ErrMissingSignature = errors.New("webhook: missing Webhook-Id or Webhook-Signature")
+	ErrInvalidSignature = errors.New("webhook: invalid signature")
+	ErrExpired          = errors.New("webhook: timestamp outside tolerance")
+	ErrDuplicate        = errors.New("webhook: delivery already processed")
+	ErrTooLarge         = errors.New("webhook: body too large")
+)
+
+// Sign returns the Webhook-Signature value for a delivery sent at t.
+func Sign(secret []byte, id string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac(secret, id, ts, body))
+}
+
+func mac(secret []byte, id, ts string, body []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(id + "." + ts + "."))
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// NewRequest builds a signed JSON POST of body to url. id identifies the
+// event and must stay the same when a failed delivery is retried later;
+// the signature is made afresh each time, with the current time.
+func NewRequest(ctx context.Context, url string, secret []byte, id string, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IDHeader, id)
+	req.Header.Set("Idempotency-Key", id)
+	req.Header.Set(SignatureHeader, Sign(secret, id, time.Now(), body))
+	return req, nil
+}
+
+type Verifier struct {
+	// Secrets are tried in turn, so a secret can be rotated by adding the
+	// new one before senders switch to it and removing the old one after.
+	Secrets   [][]byte
+	Tolerance time.Duration
+	Clock     clock.Clock
+
+	mu        sync.Mutex
+	processed map[string]time.Time // Webhook-Id -> when it can be forgotten
+}
+
+// NewVerifier returns a Verifier for secrets with DefaultTolerance.
+func NewVerifier(secrets ...[]byte) *Verifier {
+	return &Verifier{Secrets: secrets, Tolerance: DefaultTolerance, Clock: clock.Real{}}
+}
+
+// Verify reads r's body and returns it if the delivery is authentic and
+// recent, or ErrDuplicate if its ID was marked processed. The body is also
+// put back on r. Processed IDs are kept in memory, so with several receiver
+// instances a replay to another instance is only stopped by the timestamp
+// check, and two copies arriving at once can both get through; receivers
+// that must act exactly once should also dedupe by Webhook-Id in storage.
+func (v *Verifier) Verify(r *http.Request) ([]byte, error) {
+	id := r.Header.Get(IDHeader)
+	ts, sigs, ok := parseSignature(r.Header.Get(SignatureHeader))
+	if id == "" || !ok {
+		return nil, ErrMissingSignature
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, MaxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > MaxBodySize {
+		return nil, ErrTooLarge
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if !v.validMAC(id, ts, body, sigs) {
+		return nil, ErrInvalidSignature
+	}
+
+	// checked after the MAC, so an unsigned request learns nothing about
+	// the receiver's clock
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	now := v.Clock.Now()
+	sent := time.Unix(sec, 0)
+	if sent.Before(now.Add(-v.Tolerance)) || sent.After(now.Add(v.Tolerance)) {
+		return nil, ErrExpired
+	}
+
+	if v.isProcessed(id, now) {
+		return nil, ErrDuplicate
+	}
+	return body, nil
+}
+
+func (v *Verifier) validMAC(id, ts string, body []byte, sigs [][]byte) bool {
+	for _, secret := range v.Secrets {
+		want := mac(secret, id, ts, body)
+		for _, sig := range sigs {
+			if hmac.Equal(sig, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MarkProcessed records that the delivery with Webhook-Id id was handled,
+// so Verify answers ErrDuplicate for it from now on. Call it only once the
+// handler succeeded.
+//
+// The ID is kept for twice Tolerance: a delivery signed up to Tolerance in
+// the future still passes the timestamp check that long. A replay after
+// that fails the timestamp check instead.
+func (v *Verifier) MarkProcessed(id string) {
+	now := v.Clock.Now()
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.processed == nil {
+		v.processed = map[string]time.Time{}
+	}
+	for k, until := range v.processed {
+		if now.After(until) {
+			delete(v.processed, k)
+		}
+	}
+	v.processed[id] = now.Add(2 * v.Tolerance)
+}
+
+func (v *Verifier) isProcessed(id string, now time.Time) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	until, ok := v.processed[id]
+	return ok && !now.After(until)
+}
+
+// parseSignature splits "t=<unix>,v1=<hex>[,v1=<hex>...]". Several v1
+// values let a sender sign with an old and a new secret during rotation.
+func parseSignature(h string) (ts string, sigs [][]byte, ok bool) {
+	for _, part := range strings.Split(h, ",") {
+		k, val, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			ts = val
+		case "v1":
+			if sig, err := hex.DecodeString(val); err == nil {
+				sigs = append(sigs, sig)
+			}
+		}
+	}
+	return ts, sigs, ts != "" && len(sigs) > 0
+}
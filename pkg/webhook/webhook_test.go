@@ -0,0 +1,168 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/clock"
+)
+
+var secret = []byte("s3cret")
+
+// delivery builds a signed request as a sender at t would send it.
+func delivery(t *testing.T, id string, at time.Time, body []byte, secret []byte) *http.Request {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/hooks", bytes.NewReader(body))
+	req.Header.Set(IDHeader, id)
+	req.Header.Set(SignatureHeader, Sign(secret, id, at, body))
+	return req
+}
+
+func newVerifier(now time.Time, secrets ...[]byte) *Verifier {
+	v := NewVerifier(secrets...)
+	v.Clock = clock.NewFake(now)
+	return v
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	body := []byte(`{"event":"todo.created"}`)
+
+	tests := []struct {
+		name    string
+		req     func() *http.Request
+		secrets [][]byte
+		wantErr error
+	}{
+		{name: "valid", req: func() *http.Request { return delivery(t, "evt_1", now, body, secret) }},
+		{name: "clock skew within tolerance", req: func() *http.Request { return delivery(t, "evt_1", now.Add(4*time.Minute), body, secret) }},
+		{name: "old secret during rotation", secrets: [][]byte{[]byte("new"), secret}, req: func() *http.Request { return delivery(t, "evt_1", now, body, secret) }},
+		{name: "wrong secret", req: func() *http.Request { return delivery(t, "evt_1", now, body, []byte("other")) }, wantErr: ErrInvalidSignature},
+		{name: "expired", req: func() *http.Request { return delivery(t, "evt_1", now.Add(-6*time.Minute), body, secret) }, wantErr: ErrExpired},
+		{
+			name: "tampered body",
+			req: func() *http.Request {
+				r := delivery(t, "evt_1", now, body, secret)
+				r.Body = io.NopCloser(bytes.NewReader([]byte(`{"event":"todo.deleted"}`)))
+				return r
+			},
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name: "swapped id",
+			req: func() *http.Request {
+				r := delivery(t, "evt_1", now, body, secret)
+				r.Header.Set(IDHeader, "evt_2")
+				return r
+			},
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "unsigned",
+			req:     func() *http.Request { return httptest.NewRequest(http.MethodPost, "/hooks", bytes.NewReader(body)) },
+			wantErr: ErrMissingSignature,
+		},
+		{
+			name: "too large",
+			req: func() *http.Request {
+				return delivery(t, "evt_1", now, bytes.Repeat([]byte("x"), MaxBodySize+1), secret)
+			},
+			wantErr: ErrTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secrets := tt.secrets
+			if secrets == nil {
+				secrets = [][]byte{secret}
+			}
+			v := newVerifier(now, secrets...)
+
+			r := tt.req()
+			got, err := v.Verify(r)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !bytes.Equal(got, body) {
+				t.Errorf("Verify() body = %q, want %q", got, body)
+			}
+			// the body is put back for the handler
+			if again, _ := io.ReadAll(r.Body); !bytes.Equal(again, body) {
+				t.Errorf("r.Body after Verify = %q, want %q", again, body)
+			}
+		})
+	}
+}
+
+func TestRetryAfterReceiverFailure(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	v := newVerifier(now, secret)
+	body := []byte(`{}`)
+
+	// the first attempt verifies, but the handler fails and does not mark it
+	if _, err := v.Verify(delivery(t, "evt_1", now, body, secret)); err != nil {
+		t.Fatalf("first delivery: %v", err)
+	}
+
+	// the sender retries under the same ID, re-signed a minute later
+	v.Clock.(*clock.Fake).Advance(time.Minute)
+	if _, err := v.Verify(delivery(t, "evt_1", now.Add(time.Minute), body, secret)); err != nil {
+		t.Fatalf("retry after a receiver failure: %v", err)
+	}
+	v.MarkProcessed("evt_1")
+
+	// from now on it is a duplicate, whether replayed or retried
+	for _, at := range []time.Time{now, now.Add(time.Minute)} {
+		if _, err := v.Verify(delivery(t, "evt_1", at, body, secret)); !errors.Is(err, ErrDuplicate) {
+			t.Errorf("delivery signed at %s after MarkProcessed: error = %v, want ErrDuplicate", at, err)
+		}
+	}
+	if _, err := v.Verify(delivery(t, "evt_2", now.Add(time.Minute), body, secret)); err != nil {
+		t.Errorf("another event: %v", err)
+	}
+}
+
+func TestProcessedIDsAreForgotten(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	v := newVerifier(now, secret)
+	fake := v.Clock.(*clock.Fake)
+	v.MarkProcessed("evt_1")
+
+	// a copy signed Tolerance ahead is still fresh just before the ID goes
+	fake.Advance(2*DefaultTolerance - time.Second)
+	if _, err := v.Verify(delivery(t, "evt_1", now.Add(DefaultTolerance), []byte(`{}`), secret)); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("inside the window: error = %v, want ErrDuplicate", err)
+	}
+
+	fake.Advance(2 * time.Second)
+	v.MarkProcessed("evt_2") // prunes expired IDs
+	v.mu.Lock()
+	_, kept := v.processed["evt_1"]
+	v.mu.Unlock()
+	if kept {
+		t.Error("evt_1 still kept after twice the tolerance")
+	}
+}
+
+func TestNewRequest(t *testing.T) {
+	body := []byte(`{"event":"todo.created"}`)
+	req, err := NewRequest(context.Background(), "http://example.com/hooks", secret, "evt_1", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := req.Header.Get("Idempotency-Key"); got != "evt_1" {
+		t.Errorf("Idempotency-Key = %q, want evt_1", got)
+	}
+	if _, err := NewVerifier(secret).Verify(req); err != nil {
+		t.Errorf("Verify(NewRequest(...)): %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+// Package app holds the q1 routes so they can be exercised with httptest and
+// mounted by other binaries; cmd/main.go is only bootstrap.
+package app
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/buildinfo"
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	"github.com/labstack/echo/v4"
+)
+
+// NewServer returns the Echo app with all routes registered.
+func NewServer() *echo.Echo {
+	e := echo.New()
+
+	st := newStats()
+	e.Use(st.count)
+
+	e.GET("/", hello)
+	e.GET("/version", version)
+	e.GET("/stats", st.handle)
+
+	return e
+}
+
+func hello(c echo.Context) error {
+	return c.String(200, "Hello world")
+}
+
+// version identifies the running binary: version, git commit and build time.
+func version(c echo.Context) error {
+	return c.JSON(http.StatusOK, buildinfo.Get())
+}
+
+// Listen opens the listener up front, so the real address is known even when
+// the port is 0 (OS-assigned), and wires it and the timeouts into e. Tests can
+// dial the returned listener's Addr() directly.
+func Listen(e *echo.Echo, srv config.Server) (net.Listener, error) {
+	ln, err := srv.Listen(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	// StartServer serves on e.Listener when it is set instead of binding again
+	e.Listener = ln
+
+	// e.Server is the real http.Server, so timeouts set here apply to it.
+	// Without them a slow client can hold a connection open forever.
+	srv.Apply(e.Server)
+
+	// the caller logs the address itself
+	e.HidePort = true
+
+	return ln, nil
+}
@@ -0,0 +1,40 @@
+package app
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// stats is state shared by every request. Handlers run concurrently, one
+// goroutine per request, so the counter must be atomic: a plain `n++` from
+// many goroutines is a data race and loses increments.
+type stats struct {
+	started  time.Time
+	requests atomic.Uint64
+}
+
+func newStats() *stats {
+	return &stats{started: time.Now()}
+}
+
+// count is middleware that increments the request counter for every request.
+func (s *stats) count(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		s.requests.Add(1)
+		return next(c)
+	}
+}
+
+func (s *stats) handle(c echo.Context) error {
+	uptime := time.Since(s.started)
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"started_at":     s.started.UTC(),
+		"uptime":         uptime.Round(time.Second).String(),
+		"uptime_seconds": int64(uptime.Seconds()),
+		"requests":       s.requests.Load(),
+	})
+}
@@ -1,17 +1,35 @@
 package main
 
 // Hello World API: Create a simple Echo server with GET / that returns "Hello, World!".
-import "github.com/labstack/echo/v4"
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	"github.com/jabeedhexanovamedia/hello-echo/app"
+)
 
 func main() {
 
-	e := echo.New()
+	// BIND_ADDR / PORT override the defaults: localhost on a random free port
+	srv, err := config.LoadServer(config.DefaultServer("0"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	e := app.NewServer()
+
+	ln, err := app.Listen(e, srv)
+	if err != nil {
+		log.Fatalf("failed to listen on %s: %v", srv.Addr(), err)
+	}
 
-	e.GET("/", func(c echo.Context) error {
+	log.Printf("listening on http://%s", ln.Addr())
 
-		return c.String(200, "Hello world")
-	})
 	// e.Logger.Fatal(e.Start(":8080"))
 
-	e.Start(":0")
+	if err := e.StartServer(e.Server); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("server stopped: %v", err)
+	}
 }
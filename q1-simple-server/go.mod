@@ -5,7 +5,11 @@ go 1.24.0
 toolchain go1.24.11
 
 require (
-	github.com/labstack/echo/v4 v4.15.0 // indirect
+	github.com/jabeedhexanovamedia/go-echo-practice/pkg v0.0.0
+	github.com/labstack/echo/v4 v4.15.0
+)
+
+require (
 	github.com/labstack/gommon v0.4.2 // indirect
 	github.com/mattn/go-colorable v0.1.14 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
@@ -16,3 +20,5 @@ require (
 	golang.org/x/sys v0.39.0 // indirect
 	golang.org/x/text v0.32.0 // indirect
 )
+
+replace github.com/jabeedhexanovamedia/go-echo-practice/pkg => ../pkg
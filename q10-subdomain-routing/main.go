@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"html"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// Subdomain Routing: one server, one port, three sites picked by the Host
+// header:
+//
+//	api.localhost    JSON API
+//	admin.localhost  admin pages
+//	anything else    the main site
+//
+// Most browsers and curl resolve *.localhost to 127.0.0.1 by themselves. If
+// yours does not, add this line to /etc/hosts (C:\Windows\System32\drivers\etc\hosts
+// on Windows):
+//
+//	127.0.0.1  localhost api.localhost admin.localhost
+//
+// In production the same names are DNS records (A/CNAME for api.example.com
+// and admin.example.com, or one wildcard *.example.com) pointing at this
+// server; set DOMAIN=example.com.
+func main() {
+	e := echo.New()
+	e.Use(middleware.Logger())
+
+	domain := os.Getenv("DOMAIN")
+	if domain == "" {
+		domain = "localhost"
+	}
+
+	// Echo looks up the router by the raw Host header, which includes the
+	// port ("api.localhost:3000"). Normalising it in Pre, which runs before
+	// routing, means hosts can be registered without a port.
+	e.Pre(normalizeHost)
+
+	api := e.Host("api."+domain, middleware.CORS())
+	api.GET("/", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, echo.Map{"site": "api", "version": "v1"})
+	})
+	api.GET("/users", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, []echo.Map{{"id": 1, "name": "John"}})
+	})
+
+	admin := e.Host("admin."+domain, noIndex)
+	admin.GET("/", func(c echo.Context) error {
+		return c.HTML(http.StatusOK, "<h1>Admin</h1>")
+	})
+
+	// routes on e itself form the default router, used for every host
+	// without its own (localhost, www.localhost, an IP address, ...)
+	e.GET("/", func(c echo.Context) error {
+		return c.HTML(http.StatusOK, "<h1>Main site</h1><p>Host: "+html.EscapeString(c.Request().Host)+"</p>")
+	})
+
+	srv, err := config.LoadServer(config.DefaultServer("3000"))
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	srv.Apply(e.Server)
+
+	if err := e.Start(srv.Addr()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		e.Logger.Fatal(err)
+	}
+}
+
+// normalizeHost lowercases the Host header and drops its port and any
+// trailing dot, so "API.localhost.:3000" matches the "api.localhost" router.
+func normalizeHost(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		r := c.Request()
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		r.Host = strings.TrimSuffix(strings.ToLower(host), ".")
+		return next(c)
+	}
+}
+
+// noIndex keeps search engines away from the admin site.
+func noIndex(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		c.Response().Header().Set("X-Robots-Tag", "noindex, nofollow")
+		return next(c)
+	}
+}
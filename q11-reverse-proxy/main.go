@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// Reverse Proxy: Echo's Proxy middleware spreading requests over several
+// upstreams with round-robin and random balancers, rewriting paths and
+// headers on the way, and passing WebSocket connections straight through.
+//
+// UPSTREAMS=http://10.0.0.1:8080,http://10.0.0.2:8080 proxies to real
+// servers; without it three demo upstreams are started in-process.
+func main() {
+	e := echo.New()
+	e.Use(middleware.Logger())
+
+	urls, err := upstreams()
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+
+	// /rr/* and /ws take turns across the upstreams; /random/* picks one at
+	// random. Each balancer keeps its own position in the rotation.
+	rr := middleware.NewRoundRobinBalancer(targets(urls))
+	random := middleware.NewRandomBalancer(targets(urls))
+
+	// a group with middleware catches every path under its prefix, so the
+	// proxy needs no route of its own
+	e.Group("/rr", stripClientHeaders, proxy(rr, len(urls), map[string]string{"/rr/*": "/$1"}))
+	e.Group("/random", stripClientHeaders, proxy(random, len(urls), map[string]string{"/random/*": "/$1"}))
+
+	// An Upgrade: websocket request is detected by the middleware and proxied
+	// as raw TCP after the handshake, so frames flow both ways untouched.
+	// Hijacked connections drop the server's read/write deadlines, so
+	// the 10s timeouts do not cut long-lived sockets.
+	e.GET("/ws", echo.NotFoundHandler, proxy(middleware.NewRoundRobinBalancer(targets(urls)), 0, nil))
+
+	srv, err := config.LoadServer(config.DefaultServer("3000"))
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	srv.Apply(e.Server)
+
+	if err := e.Start(srv.Addr()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		e.Logger.Fatal(err)
+	}
+}
+
+// proxy forwards to b. retries > 0 sends a request whose upstream refused
+// the connection (502) on to the next target instead of failing it.
+func proxy(b middleware.ProxyBalancer, retries int, rewrite map[string]string) echo.MiddlewareFunc {
+	return middleware.ProxyWithConfig(middleware.ProxyConfig{
+		Balancer:   b,
+		Rewrite:    rewrite,
+		RetryCount: retries,
+		ModifyResponse: func(res *http.Response) error {
+			// say which upstream answered, and stop leaking what it runs
+			res.Header.Set("X-Upstream", res.Request.URL.Host)
+			res.Header.Del("Server")
+			res.Header.Del("X-Powered-By")
+			return nil
+		},
+	})
+}
+
+// stripClientHeaders rewrites the request before it is proxied: internal
+// headers a client could forge are removed, and the proxy marks itself.
+func stripClientHeaders(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		h := c.Request().Header
+		h.Del("X-Internal-User")
+		h.Del("Cookie") // the upstreams are stateless APIs
+		h.Set("X-Proxy", "q11-reverse-proxy")
+		return next(c)
+	}
+}
+
+func targets(urls []*url.URL) []*middleware.ProxyTarget {
+	t := make([]*middleware.ProxyTarget, len(urls))
+	for i, u := range urls {
+		t[i] = &middleware.ProxyTarget{Name: fmt.Sprintf("upstream-%d", i+1), URL: u}
+	}
+	return t
+}
+
+func upstreams() ([]*url.URL, error) {
+	if v := os.Getenv("UPSTREAMS"); v != "" {
+		var urls []*url.URL
+		for _, raw := range strings.Split(v, ",") {
+			u, err := url.Parse(strings.TrimSpace(raw))
+			if err != nil || u.Host == "" {
+				return nil, fmt.Errorf("UPSTREAMS: invalid URL %q", raw)
+			}
+			urls = append(urls, u)
+		}
+		return urls, nil
+	}
+
+	var urls []*url.URL
+	for _, name := range []string{"alpha", "beta", "gamma"} {
+		u, err := startUpstream(name)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, u)
+	}
+	return urls, nil
+}
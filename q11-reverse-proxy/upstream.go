@@ -0,0 +1,64 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+)
+
+var upgrader = websocket.Upgrader{}
+
+// startUpstream runs a tiny backend on a random local port so the example
+// works without anything else running. It reports its name, the path it
+// saw and the headers the proxy added, and echoes WebSocket messages.
+func startUpstream(name string) (*url.URL, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+	e.Listener = ln
+
+	e.GET("/ws", func(c echo.Context) error {
+		conn, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
+		if err != nil {
+			return nil
+		}
+		defer conn.Close()
+
+		for {
+			mt, msg, err := conn.ReadMessage()
+			if err != nil {
+				return nil
+			}
+			if err := conn.WriteMessage(mt, append([]byte(name+": "), msg...)); err != nil {
+				return nil
+			}
+		}
+	})
+
+	e.Any("/*", func(c echo.Context) error {
+		h := c.Request().Header
+		return c.JSON(http.StatusOK, echo.Map{
+			"upstream": name,
+			"path":     c.Request().URL.Path,
+			"headers": echo.Map{
+				"X-Forwarded-For":   h.Get("X-Forwarded-For"),
+				"X-Forwarded-Proto": h.Get("X-Forwarded-Proto"),
+				"X-Real-Ip":         h.Get("X-Real-Ip"),
+				"X-Proxy":           h.Get("X-Proxy"),
+				"Cookie":            h.Get("Cookie"),
+			},
+		})
+	})
+
+	go func() { _ = e.Start("") }()
+
+	return &url.URL{Scheme: "http", Host: ln.Addr().String()}, nil
+}
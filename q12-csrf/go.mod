@@ -0,0 +1,27 @@
+module github.com/jabeedhexanovamedia/csrf
+
+go 1.24.0
+
+toolchain go1.24.11
+
+require (
+	github.com/jabeedhexanovamedia/go-echo-practice/pkg v0.0.0
+	github.com/labstack/echo/v4 v4.15.0
+)
+
+require (
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/labstack/gommon v0.4.2 // indirect
+	github.com/mattn/go-colorable v0.1.14 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/oklog/ulid/v2 v2.1.1 // indirect
+	github.com/valyala/bytebufferpool v1.0.0 // indirect
+	github.com/valyala/fasttemplate v1.2.2 // indirect
+	golang.org/x/crypto v0.46.0 // indirect
+	golang.org/x/net v0.48.0 // indirect
+	golang.org/x/sys v0.39.0 // indirect
+	golang.org/x/text v0.32.0 // indirect
+	golang.org/x/time v0.14.0 // indirect
+)
+
+replace github.com/jabeedhexanovamedia/go-echo-practice/pkg => ../pkg
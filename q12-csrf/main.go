@@ -0,0 +1,139 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"html/template"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	sharedmw "github.com/jabeedhexanovamedia/go-echo-practice/pkg/middleware"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+//go:embed index.html
+var indexHTML string
+
+var page = template.Must(template.New("index").Parse(indexHTML))
+
+// guestbook is the state the form changes, kept in memory for the example.
+type guestbook struct {
+	mu       sync.RWMutex
+	comments []string
+}
+
+func (g *guestbook) add(text string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.comments = append(g.comments, text)
+}
+
+func (g *guestbook) list() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return append([]string(nil), g.comments...)
+}
+
+// CSRF Protection: a server-rendered form protected by Echo's CSRF
+// middleware. GET requests get a random token in the _csrf cookie and in
+// the page; a POST is accepted only when the token it sends back matches
+// the cookie, which a page on another site cannot read.
+//
+// /api/* is exempt: it authenticates with an Authorization header instead
+// of cookies, and a browser never attaches that header on its own, so
+// there is nothing for a forged cross-site request to ride on.
+//
+// COOKIE_SECURE=true marks the cookie Secure (set it when serving HTTPS).
+func main() {
+	e := echo.New()
+	e.Use(middleware.Logger())
+
+	e.Use(middleware.CSRFWithConfig(middleware.CSRFConfig{
+		Skipper: func(c echo.Context) bool {
+			return strings.HasPrefix(c.Path(), "/api/")
+		},
+		// forms post the token as a field, fetch/XHR callers as a header
+		TokenLookup:    "form:_csrf,header:X-CSRF-Token",
+		ContextKey:     "csrf",
+		CookieName:     "_csrf",
+		CookiePath:     "/",
+		CookieHTTPOnly: true, // the page renders the token; scripts never need the cookie
+		CookieSameSite: http.SameSiteLaxMode,
+		CookieSecure:   os.Getenv("COOKIE_SECURE") == "true",
+		CookieMaxAge:   3600,
+		ErrorHandler: func(err error, c echo.Context) error {
+			return c.HTML(http.StatusForbidden, `<h1>Forbidden</h1><p>The form expired or did not come from this site. <a href="/">Reload</a> and try again.</p>`)
+		},
+	}))
+
+	book := &guestbook{}
+
+	e.GET("/", func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
+		return page.Execute(c.Response(), map[string]any{
+			"CSRF":     c.Get("csrf"),
+			"Comments": book.list(),
+		})
+	})
+
+	// the handler only runs once the middleware has checked the token
+	e.POST("/comments", func(c echo.Context) error {
+		text := strings.TrimSpace(c.FormValue("text"))
+		if text == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "text is required")
+		}
+		book.add(text)
+		// Post/Redirect/Get: reloading the page does not resubmit the form
+		return c.Redirect(http.StatusSeeOther, "/")
+	})
+
+	api := e.Group("/api", requireToken(apiToken()), requireJSON)
+	api.GET("/comments", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, book.list())
+	})
+	api.POST("/comments", func(c echo.Context) error {
+		var req struct {
+			Text string `json:"text"`
+		}
+		if err := c.Bind(&req); err != nil {
+			return err
+		}
+		if strings.TrimSpace(req.Text) == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "text is required")
+		}
+		book.add(strings.TrimSpace(req.Text))
+		return c.NoContent(http.StatusCreated)
+	})
+
+	srv, err := config.LoadServer(config.DefaultServer("3000"))
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	srv.Apply(e.Server)
+
+	if err := e.Start(srv.Addr()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		e.Logger.Fatal(err)
+	}
+}
+
+func apiToken() string {
+	if v := os.Getenv("API_TOKEN"); v != "" {
+		return v
+	}
+	return "secret"
+}
+
+// requireToken is the reason /api can skip CSRF: credentials travel in a
+// header the client sets explicitly, never in a cookie the browser adds.
+func requireToken(token string) echo.MiddlewareFunc {
+	return echo.WrapMiddleware(sharedmw.BearerAuth(sharedmw.Keys(token)))
+}
+
+// requireJSON rejects state-changing API calls that are not JSON. An HTML
+// form cannot send application/json, and a cross-site fetch that does set
+// it needs a CORS preflight this server never approves.
+var requireJSON = echo.WrapMiddleware(sharedmw.RequireContentType(echo.MIMEApplicationJSON))
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// defaultCSP is the policy the app should send with no CSP_* env vars,
+// written out by hand so a change to DefaultCSP or String shows up here.
+const defaultCSP = "default-src 'self'; script-src 'self'; style-src 'self'; img-src 'self' data:; " +
+	"connect-src 'self'; frame-ancestors 'none'; form-action 'self'; object-src 'none'; base-uri 'self'; " +
+	"report-uri /csp-report"
+
+// get sends GET / through the app built from the current env vars.
+func get(t *testing.T, https bool) http.Header {
+	t.Helper()
+	cfg, err := loadSecureConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := newServer(cfg)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if https {
+		// what a TLS-terminating proxy sets; Echo trusts it for HSTS
+		req.Header.Set(echo.HeaderXForwardedProto, "https")
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET / = %d", rec.Code)
+	}
+	return rec.Header()
+}
+
+func TestDefaultHeaders(t *testing.T) {
+	h := get(t, false)
+
+	want := map[string]string{
+		echo.HeaderXFrameOptions:                   "DENY",
+		echo.HeaderXContentTypeOptions:             "nosniff",
+		echo.HeaderXXSSProtection:                  "0",
+		echo.HeaderReferrerPolicy:                  "strict-origin-when-cross-origin",
+		echo.HeaderContentSecurityPolicy:           defaultCSP,
+		echo.HeaderContentSecurityPolicyReportOnly: "",
+		// never sent over plain HTTP
+		echo.HeaderStrictTransportSecurity: "",
+	}
+	for name, v := range want {
+		if got := h.Get(name); got != v {
+			t.Errorf("%s = %q, want %q", name, got, v)
+		}
+	}
+}
+
+func TestHSTS(t *testing.T) {
+	tests := []struct {
+		name   string
+		maxAge string // HSTS_MAX_AGE; empty keeps the default
+		https  bool
+		want   string
+	}{
+		{name: "plain http", https: false, want: ""},
+		{name: "https", https: true, want: "max-age=31536000; includeSubdomains"},
+		{name: "custom max-age", maxAge: "300", https: true, want: "max-age=300; includeSubdomains"},
+		{name: "turned off", maxAge: "0", https: true, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.maxAge != "" {
+				t.Setenv("HSTS_MAX_AGE", tt.maxAge)
+			}
+			if got := get(t, tt.https).Get(echo.HeaderStrictTransportSecurity); got != tt.want {
+				t.Errorf("Strict-Transport-Security = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSPReportOnly(t *testing.T) {
+	t.Setenv("CSP_REPORT_ONLY", "true")
+	h := get(t, false)
+
+	if got := h.Get(echo.HeaderContentSecurityPolicyReportOnly); got != defaultCSP {
+		t.Errorf("Content-Security-Policy-Report-Only = %q, want %q", got, defaultCSP)
+	}
+	if got := h.Get(echo.HeaderContentSecurityPolicy); got != "" {
+		t.Errorf("Content-Security-Policy = %q, want it unset in report-only mode", got)
+	}
+}
+
+func TestCSPFromEnv(t *testing.T) {
+	t.Setenv("CSP_SCRIPT_SRC", "'self', https://cdn.jsdelivr.net")
+
+	want := "default-src 'self'; script-src 'self' https://cdn.jsdelivr.net; style-src 'self'; img-src 'self' data:; " +
+		"connect-src 'self'; frame-ancestors 'none'; form-action 'self'; object-src 'none'; base-uri 'self'; " +
+		"report-uri /csp-report"
+	if got := get(t, false).Get(echo.HeaderContentSecurityPolicy); got != want {
+		t.Errorf("Content-Security-Policy = %q, want %q", got, want)
+	}
+}
+
+func TestBadEnv(t *testing.T) {
+	t.Setenv("HSTS_MAX_AGE", "a year")
+	if _, err := loadSecureConfig(); err == nil {
+		t.Error("loadSecureConfig accepted HSTS_MAX_AGE=\"a year\"")
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+)
+
+// secrets maps a name to its secret: username -> password for basic auth,
+// client name -> key for key auth. Loaded from "name:secret,name:secret".
+type secrets map[string]string
+
+func parseSecrets(raw string) (secrets, error) {
+	s := secrets{}
+	for _, pair := range strings.Split(raw, ",") {
+		name, secret, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || name == "" || secret == "" {
+			return nil, fmt.Errorf("invalid entry %q, want name:secret", pair)
+		}
+		s[name] = secret
+	}
+	return s, nil
+}
+
+// equal compares in constant time. Hashing first makes both sides the same
+// length, so the comparison does not even leak how long the secret is.
+func equal(a, b string) bool {
+	ha, hb := sha256.Sum256([]byte(a)), sha256.Sum256([]byte(b))
+	return subtle.ConstantTimeCompare(ha[:], hb[:]) == 1
+}
+
+// checkPassword reports whether user exists with this password. An unknown
+// user still costs one comparison, so response times do not reveal which
+// usernames are valid.
+func (s secrets) checkPassword(user, password string) bool {
+	want, ok := s[user]
+	if !ok {
+		want = "\x00unknown-user"
+	}
+	return equal(password, want) && ok
+}
+
+// lookupKey returns the client owning key. Every key is compared, with no
+// early return, so the time taken does not depend on which one matched.
+func (s secrets) lookupKey(key string) (string, bool) {
+	var client string
+	for name, secret := range s {
+		if equal(key, secret) {
+			client = name
+		}
+	}
+	return client, client != ""
+}
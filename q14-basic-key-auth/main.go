@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// Basic Auth vs Key Auth: the same server protecting /admin with HTTP Basic
+// auth (a username and password, with a browser login prompt) and /api
+// with key auth (a per-client key sent in a header).
+//
+//	BASIC_REALM   realm shown in the browser prompt (default "Admin area")
+//	BASIC_USERS   user:password pairs (default admin:admin)
+//	API_REALM     realm in the key auth challenge (default "API")
+//	API_KEYS      client:key pairs (default demo:demo-key)
+func main() {
+	e := echo.New()
+	e.Use(middleware.Logger())
+
+	// string env vars cannot fail to parse, so the errors are dropped
+	realm, _ := config.GetEnv("BASIC_REALM", "Admin area")
+	apiRealm, _ := config.GetEnv("API_REALM", "API")
+	rawUsers, _ := config.GetEnv("BASIC_USERS", "admin:admin")
+	rawKeys, _ := config.GetEnv("API_KEYS", "demo:demo-key")
+
+	users, err := parseSecrets(rawUsers)
+	if err != nil {
+		e.Logger.Fatal("BASIC_USERS: ", err)
+	}
+	keys, err := parseSecrets(rawKeys)
+	if err != nil {
+		e.Logger.Fatal("API_KEYS: ", err)
+	}
+
+	e.GET("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, "public\n")
+	})
+
+	// Basic auth: the browser shows a login box on the 401 and then resends
+	// the credentials with every request to this realm until it is closed.
+	admin := e.Group("/admin", middleware.BasicAuthWithConfig(middleware.BasicAuthConfig{
+		Realm: realm,
+		Validator: func(user, password string, c echo.Context) (bool, error) {
+			if !users.checkPassword(user, password) {
+				return false, nil
+			}
+			c.Set("user", user)
+			return true, nil
+		},
+	}))
+	admin.GET("", func(c echo.Context) error {
+		return c.String(http.StatusOK, "hello "+c.Get("user").(string)+"\n")
+	})
+
+	// Key auth: a machine client sends its key on every call. X-API-Key is
+	// tried first, then "Authorization: Bearer <key>".
+	api := e.Group("/api", middleware.KeyAuthWithConfig(middleware.KeyAuthConfig{
+		KeyLookup: "header:X-API-Key,header:Authorization:Bearer ",
+		Validator: func(key string, c echo.Context) (bool, error) {
+			client, ok := keys.lookupKey(key)
+			if ok {
+				c.Set("client", client)
+			}
+			return ok, nil
+		},
+		// the default answers 400 for a missing key and 401 without a
+		// challenge for a wrong one; clients handle one 401 more easily
+		ErrorHandler: func(err error, c echo.Context) error {
+			c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer realm="+strconv.Quote(apiRealm))
+			return echo.NewHTTPError(http.StatusUnauthorized, "missing or invalid API key").SetInternal(err)
+		},
+	}))
+	api.GET("/whoami", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, echo.Map{"client": c.Get("client")})
+	})
+
+	srv, err := config.LoadServer(config.DefaultServer("3000"))
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	srv.Apply(e.Server)
+
+	if err := e.Start(srv.Addr()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		e.Logger.Fatal(err)
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// Redirects and Trailing Slashes: canonical URLs enforced in Pre
+// middleware, before the router picks a route.
+//
+//	DOMAIN          the site's domain (default localhost)
+//	FORCE_HTTPS     redirect http:// to https:// (default false)
+//	WWW             "www", "non-www" or "" to leave the host alone (default non-www)
+//	TRAILING_SLASH  "redirect", "rewrite" or "add" (default redirect)
+func main() {
+	e := echo.New()
+	e.Use(middleware.Logger())
+
+	domain, _ := config.GetEnv("DOMAIN", "localhost")
+	www, _ := config.GetEnv("WWW", "non-www")
+	slash, _ := config.GetEnv("TRAILING_SLASH", "redirect")
+	forceHTTPS, err := config.GetEnvBool("FORCE_HTTPS", false)
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+
+	// Echo's redirects build the Location from the Host header, so an
+	// unchecked Host would turn them into an open redirect. Only our own
+	// names get through.
+	e.Pre(allowHosts(domain, "www."+domain))
+
+	for _, mw := range hostRedirects(forceHTTPS, www) {
+		e.Pre(mw)
+	}
+
+	// Trailing-slash middleware must run in Pre: by the time e.Use
+	// middleware runs the router has already matched (or failed to match)
+	// the original path.
+	switch slash {
+	case "redirect":
+		// GET/HEAD get a 301 so browsers and crawlers learn the one URL.
+		// Other methods are rewritten in place, because a 301 turns a POST
+		// into a GET in most clients.
+		e.Pre(middleware.RemoveTrailingSlashWithConfig(middleware.TrailingSlashConfig{
+			Skipper:      func(c echo.Context) bool { return !isSafe(c.Request().Method) },
+			RedirectCode: http.StatusMovedPermanently,
+		}))
+		e.Pre(middleware.RemoveTrailingSlash())
+	case "rewrite":
+		e.Pre(middleware.RemoveTrailingSlash())
+	case "add":
+		e.Pre(middleware.AddTrailingSlash())
+	default:
+		e.Logger.Fatalf("TRAILING_SLASH=%q: want redirect, rewrite or add", slash)
+	}
+
+	// With AddTrailingSlash every request path ends in "/", so the routes
+	// have to be registered that way too.
+	route := func(path string) string {
+		if slash == "add" {
+			return path + "/"
+		}
+		return path
+	}
+
+	e.GET("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, "home\n")
+	})
+	e.GET(route("/users"), func(c echo.Context) error {
+		return c.JSON(http.StatusOK, []string{"alice", "bob"})
+	})
+	e.GET(route("/users/:id"), func(c echo.Context) error {
+		return c.JSON(http.StatusOK, echo.Map{"id": c.Param("id"), "path": c.Request().URL.Path})
+	})
+	e.POST(route("/users"), func(c echo.Context) error {
+		return c.NoContent(http.StatusCreated)
+	})
+
+	srv, err := config.LoadServer(config.DefaultServer("3000"))
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	srv.Apply(e.Server)
+
+	if err := e.Start(srv.Addr()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		e.Logger.Fatal(err)
+	}
+}
+
+// hostRedirects picks Echo's redirect middlewares so that any
+// non-canonical URL reaches the canonical one in a single hop.
+// c.Scheme() honours X-Forwarded-Proto, so this works behind a TLS proxy.
+func hostRedirects(forceHTTPS bool, www string) []echo.MiddlewareFunc {
+	moved := middleware.RedirectConfig{Code: http.StatusMovedPermanently}
+	switch {
+	case forceHTTPS && www == "www":
+		// HTTPSWWWRedirect only fires when the scheme AND the host are both
+		// wrong, so the two single-fix redirects cover the other cases
+		return []echo.MiddlewareFunc{
+			middleware.HTTPSWWWRedirectWithConfig(moved),
+			middleware.HTTPSRedirectWithConfig(moved),
+			middleware.WWWRedirectWithConfig(moved),
+		}
+	case forceHTTPS && www == "non-www":
+		// HTTPSNonWWWRedirect fixes the scheme and strips www in one go;
+		// NonWWWRedirect catches https://www.
+		return []echo.MiddlewareFunc{
+			middleware.HTTPSNonWWWRedirectWithConfig(moved),
+			middleware.NonWWWRedirectWithConfig(moved),
+		}
+	case forceHTTPS:
+		return []echo.MiddlewareFunc{middleware.HTTPSRedirectWithConfig(moved)}
+	case www == "www":
+		return []echo.MiddlewareFunc{middleware.WWWRedirectWithConfig(moved)}
+	case www == "non-www":
+		return []echo.MiddlewareFunc{middleware.NonWWWRedirectWithConfig(moved)}
+	}
+	return nil
+}
+
+// allowHosts answers 400 for a Host header that is not one of hosts,
+// ignoring the port.
+func allowHosts(hosts ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			host := c.Request().Host
+			if h, _, err := net.SplitHostPort(host); err == nil {
+				host = h
+			}
+			for _, allowed := range hosts {
+				if strings.EqualFold(host, allowed) {
+					return next(c)
+				}
+			}
+			return echo.NewHTTPError(http.StatusBadRequest, "unknown host")
+		}
+	}
+}
+
+func isSafe(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
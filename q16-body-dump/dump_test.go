@@ -0,0 +1,69 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	d := dumper{max: 2048}
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+	}{
+		{"empty", "application/json", "", "(empty)"},
+		{"json", "application/json", `{"email":"a@b.c","password":"hunter2"}`, `{"email":"a@b.c","password":"[REDACTED]"}`},
+		{"json with charset", "application/json; charset=utf-8", `{"token":"t"}`, `{"token":"[REDACTED]"}`},
+		{"nested json", "application/json", `{"user":{"Password":"x"},"items":[{"cvv":"123"}]}`, `{"items":[{"cvv":"[REDACTED]"}],"user":{"Password":"[REDACTED]"}}`},
+		{"invalid json", "application/json", `{"password":`, "(12 bytes of invalid JSON)"},
+		{"+json type", "application/merge-patch+json", `{"password":"hunter2"}`, `{"password":"[REDACTED]"}`},
+		{"json as text/plain", "text/plain", `{"password":"hunter2"}`, `{"password":"[REDACTED]"}`},
+		{"json without content type", "", `{"api_key":"k"}`, `{"api_key":"[REDACTED]"}`},
+		{"form", "application/x-www-form-urlencoded", "name=Ann&password=hunter2", "name=Ann&password=%5BREDACTED%5D"},
+		{"html", "text/html; charset=UTF-8", "<p>Welcome</p>", "<p>Welcome</p>"},
+		{"plain text", "text/plain", "ok", "ok"},
+		{"form without content type", "", "name=Ann&password=hunter2", "(25 bytes of unknown type)"},
+		{"unknown text type", "text/csv", "user,password\nann,hunter2", "(25 bytes of text/csv)"},
+		{"binary", "image/png", "\x89PNG\r\n", "(6 bytes of image/png)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := d.format(tt.contentType, []byte(tt.body))
+			if got != tt.want {
+				t.Errorf("format(%q, %q) = %q, want %q", tt.contentType, tt.body, got, tt.want)
+			}
+			if strings.Contains(got, "hunter2") {
+				t.Errorf("format(%q, ...) leaked the password: %s", tt.contentType, got)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s    string
+		max  int
+		want string
+	}{
+		{"short", 10, "short"},
+		{"exactly", 7, "exactly"},
+		{"0123456789", 4, "0123... (10 bytes total)"},
+		// never split a multi-byte rune: "é" is two bytes
+		{"aéb", 2, "a... (4 bytes total)"},
+		{"abc", 0, "... (3 bytes total)"},
+	}
+	for _, tt := range tests {
+		if got := truncate(tt.s, tt.max); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestRedactionBeforeTruncation(t *testing.T) {
+	got := dumper{max: 20}.format("application/json", []byte(`{"password":"hunter2hunter2hunter2"}`))
+	if strings.Contains(got, "hunter2") {
+		t.Errorf("truncated output leaked the password: %s", got)
+	}
+}
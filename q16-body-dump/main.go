@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"html"
+	"net/http"
+	"strings"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/labstack/gommon/log"
+)
+
+// Body Dump: request and response bodies logged for debugging with Echo's
+// BodyDump middleware, with secrets redacted and each body capped.
+//
+//	DEBUG=true          turn the dump on (off by default)
+//	DEBUG_MAX_BODY=2048 bytes of each body to log
+func main() {
+	e := echo.New()
+	e.Use(middleware.Logger())
+
+	debug, err := config.GetEnvBool("DEBUG", false)
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	maxBody, err := config.GetEnvInt("DEBUG_MAX_BODY", 2048)
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	if maxBody < 0 {
+		e.Logger.Fatalf("DEBUG_MAX_BODY=%d: must not be negative", maxBody)
+	}
+
+	// BodyDump reads the whole request into memory before the handler
+	// runs; the limit bounds that, chunked bodies included.
+	e.Use(middleware.BodyLimit("1M"))
+
+	// It also keeps a copy of the whole response, so it is only registered
+	// when asked for, and never for downloads.
+	if debug {
+		e.Logger.SetLevel(log.DEBUG)
+		e.Use(middleware.BodyDumpWithConfig(middleware.BodyDumpConfig{
+			Skipper: func(c echo.Context) bool {
+				return strings.HasPrefix(c.Path(), "/files")
+			},
+			Handler: dumper{max: maxBody}.handle,
+		}))
+	}
+
+	e.POST("/login", func(c echo.Context) error {
+		var req struct {
+			Email    string `json:"email"`
+			Password string `json:"password"`
+		}
+		if err := c.Bind(&req); err != nil {
+			return err
+		}
+		if req.Password != "secret" {
+			return echo.NewHTTPError(http.StatusUnauthorized, "invalid credentials")
+		}
+		return c.JSON(http.StatusOK, echo.Map{
+			"user":          echo.Map{"email": req.Email},
+			"access_token":  "eyJhbGciOiJIUzI1NiJ9.demo",
+			"refresh_token": "r-7f3c9a",
+		})
+	})
+
+	e.POST("/signup", func(c echo.Context) error {
+		return c.HTML(http.StatusCreated, "<p>Welcome, "+html.EscapeString(c.FormValue("name"))+"</p>")
+	})
+
+	// long enough to show the cap
+	e.GET("/report", func(c echo.Context) error {
+		rows := make([]echo.Map, 200)
+		for i := range rows {
+			rows[i] = echo.Map{"id": i + 1, "total": i * 10}
+		}
+		return c.JSON(http.StatusOK, rows)
+	})
+
+	e.GET("/files/logo.png", func(c echo.Context) error {
+		return c.Blob(http.StatusOK, "image/png", make([]byte, 1<<20))
+	})
+
+	srv, err := config.LoadServer(config.DefaultServer("3000"))
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	srv.Apply(e.Server)
+
+	if err := e.Start(srv.Addr()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		e.Logger.Fatal(err)
+	}
+}
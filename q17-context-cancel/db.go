@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Row is one result of a simulated query.
+type Row struct {
+	ID    int    `json:"id"`
+	Label string `json:"label"`
+}
+
+// DB stands in for a database driver. Like database/sql with a real
+// driver, it takes a context and gives up as soon as it is done, instead
+// of finishing work nobody is waiting for.
+type DB struct {
+	completed atomic.Int64
+	cancelled atomic.Int64
+}
+
+// Query works for cost in 100ms steps, checking ctx between them the way a
+// driver checks it while waiting on the network.
+func (db *DB) Query(ctx context.Context, name string, cost time.Duration) ([]Row, error) {
+	step := 100 * time.Millisecond
+	t := time.NewTicker(step)
+	defer t.Stop()
+
+	for done := time.Duration(0); done < cost; done += step {
+		select {
+		case <-ctx.Done():
+			db.cancelled.Add(1)
+			return nil, fmt.Errorf("query %s stopped after %s: %w", name, done, ctx.Err())
+		case <-t.C:
+		}
+	}
+
+	db.completed.Add(1)
+	return []Row{{ID: 1, Label: name + " #1"}, {ID: 2, Label: name + " #2"}}, nil
+}
+
+// Stats reports how many queries ran to the end and how many were cut short.
+func (db *DB) Stats() map[string]int64 {
+	return map[string]int64{
+		"completed": db.completed.Load(),
+		"cancelled": db.cancelled.Load(),
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// statusClientClosed is nginx's non-standard "client closed request"
+// status. Nobody receives it; it only marks the access log line.
+const statusClientClosed = 499
+
+// Context Cancellation: slow handlers that stop working when the request
+// context is done, because the client disconnected or because the
+// ContextTimeout middleware's deadline passed. The context is passed down
+// into a simulated DB call, which gives up as soon as it is cancelled.
+//
+//	QUERY_TIMEOUT  deadline for /api routes (default 2s)
+func main() {
+	e := echo.New()
+	e.Use(middleware.Logger())
+
+	timeout, err := config.GetEnvDuration("QUERY_TIMEOUT", 2*time.Second)
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+
+	db := &DB{}
+
+	// no deadline: runs until done or until the client hangs up
+	e.GET("/report", func(c echo.Context) error {
+		cost, err := costParam(c, 5*time.Second)
+		if err != nil {
+			return err
+		}
+		rows, err := db.Query(c.Request().Context(), "report", cost)
+		if err != nil {
+			return queryError(err)
+		}
+		return c.JSON(http.StatusOK, rows)
+	})
+
+	// ContextTimeout only sets a deadline on the request context. It is up
+	// to the handler to pass that context on and to return its error; the
+	// middleware turns context.DeadlineExceeded into a 503.
+	api := e.Group("/api", middleware.ContextTimeoutWithConfig(middleware.ContextTimeoutConfig{
+		Timeout: timeout,
+		ErrorHandler: func(err error, c echo.Context) error {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return echo.NewHTTPError(http.StatusServiceUnavailable, "query took longer than "+timeout.String()).SetInternal(err)
+			}
+			return err
+		},
+	}))
+
+	api.GET("/report", func(c echo.Context) error {
+		cost, err := costParam(c, time.Second)
+		if err != nil {
+			return err
+		}
+		rows, err := db.Query(c.Request().Context(), "report", cost)
+		if err != nil {
+			return queryError(err)
+		}
+		return c.JSON(http.StatusOK, rows)
+	})
+
+	// Three queries in parallel under one derived context: the first
+	// failure cancels the other two instead of letting them run on.
+	api.GET("/dashboard", func(c echo.Context) error {
+		ctx, cancel := context.WithCancel(c.Request().Context())
+		defer cancel()
+
+		queries := map[string]time.Duration{"users": 300 * time.Millisecond, "orders": 800 * time.Millisecond, "revenue": 1500 * time.Millisecond}
+		if slow, err := time.ParseDuration(c.QueryParam("slow")); err == nil {
+			queries["revenue"] = slow
+		}
+
+		var (
+			mu       sync.Mutex
+			wg       sync.WaitGroup
+			result   = map[string][]Row{}
+			firstErr error
+		)
+		for name, cost := range queries {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				rows, err := db.Query(ctx, name, cost)
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+						cancel()
+					}
+					return
+				}
+				result[name] = rows
+			}()
+		}
+		wg.Wait()
+
+		if firstErr != nil {
+			return queryError(firstErr)
+		}
+		return c.JSON(http.StatusOK, result)
+	})
+
+	e.GET("/stats", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, db.Stats())
+	})
+
+	srv, err := config.LoadServer(config.DefaultServer("3000"))
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	srv.Apply(e.Server)
+
+	if err := e.Start(srv.Addr()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		e.Logger.Fatal(err)
+	}
+}
+
+// costParam reads ?cost=, how long the simulated query takes.
+func costParam(c echo.Context, def time.Duration) (time.Duration, error) {
+	v := c.QueryParam("cost")
+	if v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "cost must be a duration like 3s")
+	}
+	return d, nil
+}
+
+// queryError maps a failed query to a response. A deadline error is
+// returned as is (wrapped) so ContextTimeout can recognise it; a cancelled
+// context means the client is gone and nothing will read the body.
+func queryError(err error) error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return err
+	case errors.Is(err, context.Canceled):
+		return echo.NewHTTPError(statusClientClosed, "client closed request").SetInternal(err)
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// Message is one entry in the feed. IDs increase by one, so a client's
+// cursor is simply the last ID it has seen.
+type Message struct {
+	ID   uint64    `json:"id"`
+	Text string    `json:"text"`
+	At   time.Time `json:"at"`
+}
+
+// Feed keeps recent messages and wakes every waiting poller when a new one
+// arrives. Waking works by closing a channel: every receiver sees a closed
+// channel at once, however many there are, which is what sync.Cond's
+// Broadcast does too, but a channel can also be used in a select with a
+// timeout and the request context.
+type Feed struct {
+	mu      sync.Mutex
+	nextID  uint64
+	history []Message // oldest first, at most size entries
+	size    int
+	changed chan struct{}
+}
+
+func NewFeed(size int) *Feed {
+	return &Feed{nextID: 1, size: size, changed: make(chan struct{})}
+}
+
+// Publish appends a message and wakes all current waiters.
+func (f *Feed) Publish(text string) Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	m := Message{ID: f.nextID, Text: text, At: time.Now().UTC()}
+	f.nextID++
+
+	f.history = append(f.history, m)
+	if len(f.history) > f.size {
+		f.history = f.history[len(f.history)-f.size:]
+	}
+
+	close(f.changed)
+	f.changed = make(chan struct{})
+	return m
+}
+
+// Since returns the messages after cursor and a channel that is closed on
+// the next Publish. Both come from one lock, so a message published right
+// after the check still wakes the caller.
+func (f *Feed) Since(cursor uint64) ([]Message, <-chan struct{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var out []Message
+	for _, m := range f.history {
+		if m.ID > cursor {
+			out = append(out, m)
+		}
+	}
+	return out, f.changed
+}
+
+// Cursor is the ID of the newest message, 0 when there is none.
+func (f *Feed) Cursor() uint64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.nextID - 1
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+//go:embed index.html
+var indexHTML []byte
+
+const (
+	defaultWait = 25 * time.Second
+	maxWait     = 30 * time.Second
+)
+
+// pollResponse is what /poll returns; clients send Cursor back as ?after=.
+type pollResponse struct {
+	Messages []Message `json:"messages"`
+	Cursor   uint64    `json:"cursor"`
+}
+
+// Long Polling: GET /poll holds the request open until a message newer
+// than ?after= arrives, or ?wait= passes, then answers and the client asks
+// again. It works with plain fetch() and through proxies that buffer
+// streams, at the cost of one request per batch of updates.
+func main() {
+	e := echo.New()
+	e.Use(middleware.Logger())
+
+	feed := NewFeed(1000)
+
+	e.GET("/", func(c echo.Context) error {
+		return c.HTMLBlob(http.StatusOK, indexHTML)
+	})
+
+	e.GET("/poll", func(c echo.Context) error {
+		after, err := strconv.ParseUint(c.QueryParam("after"), 10, 64)
+		if err != nil && c.QueryParam("after") != "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "after must be a message id")
+		}
+		wait := defaultWait
+		if v := c.QueryParam("wait"); v != "" {
+			if wait, err = time.ParseDuration(v); err != nil || wait <= 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, "wait must be a duration like 10s")
+			}
+		}
+		wait = min(wait, maxWait)
+
+		// stop the proxy and browser caching an empty answer
+		c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+
+		for {
+			msgs, changed := feed.Since(after)
+			if len(msgs) > 0 {
+				return c.JSON(http.StatusOK, pollResponse{Messages: msgs, Cursor: msgs[len(msgs)-1].ID})
+			}
+
+			select {
+			case <-changed:
+				// something was published; loop to pick it up
+			case <-timer.C:
+				// nothing new: an empty answer, and the client polls again
+				return c.JSON(http.StatusOK, pollResponse{Messages: []Message{}, Cursor: after})
+			case <-c.Request().Context().Done():
+				return nil // the client left; nobody reads a response
+			}
+		}
+	})
+
+	e.POST("/messages", func(c echo.Context) error {
+		var req struct {
+			Text string `json:"text" form:"text"`
+		}
+		if err := c.Bind(&req); err != nil {
+			return err
+		}
+		if req.Text = strings.TrimSpace(req.Text); req.Text == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "text is required")
+		}
+		return c.JSON(http.StatusCreated, feed.Publish(req.Text))
+	})
+
+	e.GET("/cursor", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, echo.Map{"cursor": feed.Cursor()})
+	})
+
+	// WriteTimeout has to outlast the longest poll, or the server cuts the
+	// connection before the handler answers
+	defaults := config.DefaultServer("3000")
+	defaults.WriteTimeout = maxWait + 10*time.Second
+
+	srv, err := config.LoadServer(defaults)
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	srv.Apply(e.Server)
+
+	if err := e.Start(srv.Addr()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		e.Logger.Fatal(err)
+	}
+}
@@ -0,0 +1,154 @@
+// Package handlers is the handler set the testkit example exercises: a
+// small notes API with an API-key middleware on writes.
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	sharedmw "github.com/jabeedhexanovamedia/go-echo-practice/pkg/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+type Note struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	Body  string `json:"body,omitempty"`
+}
+
+type NoteRequest struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// NoteStore keeps notes in memory. Each test or check builds its own, so
+// cases never see each other's data.
+type NoteStore struct {
+	mu     sync.RWMutex
+	nextID int
+	notes  map[int]Note
+}
+
+func NewNoteStore() *NoteStore {
+	return &NoteStore{nextID: 1, notes: map[int]Note{}}
+}
+
+func (s *NoteStore) Add(title, body string) Note {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := Note{ID: s.nextID, Title: title, Body: body}
+	s.notes[n.ID] = n
+	s.nextID++
+	return n
+}
+
+func (s *NoteStore) Get(id int) (Note, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	n, ok := s.notes[id]
+	return n, ok
+}
+
+func (s *NoteStore) List() []Note {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]Note, 0, len(s.notes))
+	for _, n := range s.notes {
+		out = append(out, n)
+	}
+	slices.SortFunc(out, func(a, b Note) int { return a.ID - b.ID })
+	return out
+}
+
+func (s *NoteStore) Delete(id int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.notes[id]
+	delete(s.notes, id)
+	return ok
+}
+
+type NoteHandler struct {
+	store *NoteStore
+}
+
+func NewNoteHandler(s *NoteStore) *NoteHandler {
+	return &NoteHandler{store: s}
+}
+
+// Register mounts the routes on e. Reads are public; writes need the key.
+//
+// The key check is attached per route: a Group("") with middleware would
+// also wrap the catch-all routes Echo adds for it, so unknown paths and
+// wrong methods would answer 401 instead of 404 and 405.
+func Register(e *echo.Echo, h *NoteHandler, apiKey string) {
+	requireKey := RequireAPIKey(apiKey)
+
+	e.GET("/notes", h.List)
+	e.GET("/notes/:id", h.Get)
+	e.POST("/notes", h.Create, requireKey)
+	e.DELETE("/notes/:id", h.Delete, requireKey)
+}
+
+func (h *NoteHandler) List(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.store.List())
+}
+
+func (h *NoteHandler) Get(c echo.Context) error {
+	id, err := noteID(c)
+	if err != nil {
+		return err
+	}
+	n, ok := h.store.Get(id)
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, "note not found")
+	}
+	return c.JSON(http.StatusOK, n)
+}
+
+func (h *NoteHandler) Create(c echo.Context) error {
+	var req NoteRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid JSON body")
+	}
+	req.Title = strings.TrimSpace(req.Title)
+	switch {
+	case req.Title == "":
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, "title is required")
+	case len(req.Title) > 100:
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, "title must be at most 100 characters")
+	}
+
+	n := h.store.Add(req.Title, req.Body)
+	c.Response().Header().Set(echo.HeaderLocation, "/notes/"+strconv.Itoa(n.ID))
+	return c.JSON(http.StatusCreated, n)
+}
+
+func (h *NoteHandler) Delete(c echo.Context) error {
+	id, err := noteID(c)
+	if err != nil {
+		return err
+	}
+	if !h.store.Delete(id) {
+		return echo.NewHTTPError(http.StatusNotFound, "note not found")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RequireAPIKey rejects requests without a matching X-API-Key header. It
+// is the shared pkg/middleware check adapted to Echo, so the 401 comes in
+// that package's error envelope rather than Echo's {"message":...}.
+func RequireAPIKey(key string) echo.MiddlewareFunc {
+	return echo.WrapMiddleware(sharedmw.APIKey("X-API-Key", sharedmw.Keys(key)))
+}
+
+func noteID(c echo.Context) (int, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "id must be a positive integer")
+	}
+	return id, nil
+}
@@ -0,0 +1,142 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jabeedhexanovamedia/handler-testing/handlers"
+	"github.com/jabeedhexanovamedia/handler-testing/testkit"
+	"github.com/labstack/echo/v4"
+)
+
+const apiKey = "test-key"
+
+// unauthorized is what the shared API-key middleware answers.
+const unauthorized = `{"error":{"code":"unauthorized","message":"missing or invalid X-API-Key"}}`
+
+// newServer returns a fresh app holding one note, so every case starts
+// from the same state.
+func newServer() (*echo.Echo, *handlers.NoteHandler) {
+	s := handlers.NewNoteStore()
+	s.Add("first", "hello")
+	h := handlers.NewNoteHandler(s)
+
+	e := echo.New()
+	handlers.Register(e, h, apiKey)
+	return e, h
+}
+
+// TestRoutes drives the full stack, routing and middleware included.
+func TestRoutes(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		key        string
+		body       any
+		wantStatus int
+		wantBody   string
+	}{
+		{"list", http.MethodGet, "/notes", "", nil, http.StatusOK, `[{"id":1,"title":"first","body":"hello"}]`},
+		{"get", http.MethodGet, "/notes/1", "", nil, http.StatusOK, `{"id":1,"title":"first","body":"hello"}`},
+		{"get missing", http.MethodGet, "/notes/99", "", nil, http.StatusNotFound, `{"message":"note not found"}`},
+		{"get bad id", http.MethodGet, "/notes/abc", "", nil, http.StatusBadRequest, `{"message":"id must be a positive integer"}`},
+		{"create without key", http.MethodPost, "/notes", "", handlers.NoteRequest{Title: "x"}, http.StatusUnauthorized, unauthorized},
+		{"create wrong key", http.MethodPost, "/notes", "nope", handlers.NoteRequest{Title: "x"}, http.StatusUnauthorized, unauthorized},
+		{"create malformed", http.MethodPost, "/notes", apiKey, `{"title":`, http.StatusBadRequest, `{"message":"invalid JSON body"}`},
+		{"create blank title", http.MethodPost, "/notes", apiKey, handlers.NoteRequest{Title: "   "}, http.StatusUnprocessableEntity, `{"message":"title is required"}`},
+		{"create", http.MethodPost, "/notes", apiKey, handlers.NoteRequest{Title: " second "}, http.StatusCreated, `{"id":2,"title":"second"}`},
+		{"delete", http.MethodDelete, "/notes/1", apiKey, nil, http.StatusNoContent, ""},
+		{"delete without key", http.MethodDelete, "/notes/1", "", nil, http.StatusUnauthorized, unauthorized},
+		{"delete missing", http.MethodDelete, "/notes/99", apiKey, nil, http.StatusNotFound, `{"message":"note not found"}`},
+
+		// unknown routes must not run into the key check
+		{"unknown path", http.MethodGet, "/nope", "", nil, http.StatusNotFound, `{"message":"Not Found"}`},
+		{"wrong method", http.MethodPut, "/notes/1", "", nil, http.StatusMethodNotAllowed, `{"message":"Method Not Allowed"}`},
+		{"wrong method on list", http.MethodPatch, "/notes", "", nil, http.StatusMethodNotAllowed, `{"message":"Method Not Allowed"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, _ := newServer()
+
+			req := testkit.JSONRequest(t, tt.method, tt.target, tt.body)
+			if tt.key != "" {
+				req.Header.Set("X-API-Key", tt.key)
+			}
+			rec := testkit.Serve(e, req)
+
+			testkit.AssertStatus(t, rec, tt.wantStatus)
+			if tt.wantBody != "" {
+				testkit.AssertJSON(t, rec, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestCreateSetsLocation(t *testing.T) {
+	e, _ := newServer()
+	req := testkit.JSONRequest(t, http.MethodPost, "/notes", handlers.NoteRequest{Title: "second"})
+	req.Header.Set("X-API-Key", apiKey)
+	rec := testkit.Serve(e, req)
+
+	testkit.AssertStatus(t, rec, http.StatusCreated)
+	note := testkit.DecodeJSON[handlers.Note](t, rec)
+	testkit.AssertHeader(t, rec, echo.HeaderLocation, fmt.Sprintf("/notes/%d", note.ID))
+}
+
+// TestGet calls one handler directly: no router, so the path parameter is
+// set by hand, and no middleware.
+func TestGet(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		wantStatus int
+	}{
+		{"found", "1", http.StatusOK},
+		{"missing", "2", http.StatusNotFound},
+		{"bad id", "0", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, h := newServer()
+			rec := testkit.Call(e, h.Get, testkit.JSONRequest(t, http.MethodGet, "/", nil), "id", tt.id)
+			testkit.AssertStatus(t, rec, tt.wantStatus)
+			if tt.wantStatus == http.StatusOK {
+				testkit.AssertJSON(t, rec, handlers.Note{ID: 1, Title: "first", Body: "hello"})
+			}
+		})
+	}
+}
+
+// TestRequireAPIKey checks the middleware alone, in front of a stub
+// handler: whether the request got through matters as much as the status.
+func TestRequireAPIKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		key         string
+		wantStatus  int
+		wantReached bool
+	}{
+		{"no key", "", http.StatusUnauthorized, false},
+		{"wrong key", "nope", http.StatusUnauthorized, false},
+		{"prefix of key", apiKey[:4], http.StatusUnauthorized, false},
+		{"right key", apiKey, http.StatusOK, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := testkit.JSONRequest(t, http.MethodPost, "/", nil)
+			if tt.key != "" {
+				req.Header.Set("X-API-Key", tt.key)
+			}
+			rec, reached := testkit.Middleware(echo.New(), handlers.RequireAPIKey(apiKey), req)
+
+			testkit.AssertStatus(t, rec, tt.wantStatus)
+			if reached != tt.wantReached {
+				t.Errorf("handler reached = %v, want %v", reached, tt.wantReached)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	"github.com/jabeedhexanovamedia/handler-testing/handlers"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// Testing Echo Handlers: a notes API built so every piece can be exercised
+// through httptest: routes via e.ServeHTTP, single handlers via
+// e.NewContext, and middleware on its own. The testkit package holds the
+// helpers; handlers/notes_test.go runs the table-driven cases with them.
+//
+//	API_KEY  key for POST/DELETE (default "secret")
+func main() {
+	e := echo.New()
+	e.Use(middleware.Logger())
+
+	key, _ := config.GetEnv("API_KEY", "secret")
+	handlers.Register(e, handlers.NewNoteHandler(handlers.NewNoteStore()), key)
+
+	srv, err := config.LoadServer(config.DefaultServer("3000"))
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	srv.Apply(e.Server)
+
+	if err := e.Start(srv.Addr()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		e.Logger.Fatal(err)
+	}
+}
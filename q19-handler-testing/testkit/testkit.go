@@ -0,0 +1,84 @@
+// Package testkit is pkg/testutil plus the helpers that need Echo v4's
+// context, for use from _test.go files in any Echo v4 module:
+//
+//	req := testkit.JSONRequest(t, http.MethodPost, "/notes", body)
+//	rec := testkit.Serve(e, req)
+//	testkit.AssertStatus(t, rec, http.StatusCreated)
+//	note := testkit.DecodeJSON[handlers.Note](t, rec)
+//
+// JSONRequest, Serve and the assertions are testutil's, re-exported so a
+// test needs one import. Call and Middleware are testkit's own.
+// Every helper takes a TB, which *testing.T and *testing.B satisfy.
+package testkit
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/testutil"
+	"github.com/labstack/echo/v4"
+)
+
+// TB is the part of testing.TB the helpers use.
+type TB = testutil.TB
+
+// JSONRequest builds a request with body encoded as JSON; see
+// testutil.JSONRequest.
+func JSONRequest(t TB, method, target string, body any) *http.Request {
+	t.Helper()
+	return testutil.JSONRequest(t, method, target, body)
+}
+
+// Serve sends req through e's full stack, router and middleware included,
+// without a network listener.
+func Serve(e *echo.Echo, req *http.Request) *httptest.ResponseRecorder {
+	return testutil.Serve(e, req)
+}
+
+// The assertions fail the test with the body included, so a wrong status
+// shows the error message that caused it.
+var (
+	AssertStatus = testutil.AssertStatus
+	AssertHeader = testutil.AssertHeader
+	AssertJSON   = testutil.AssertJSON
+)
+
+// DecodeJSON decodes the body into a T, failing the test if it cannot.
+func DecodeJSON[T any](t TB, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+	return testutil.DecodeJSON[T](t, rec)
+}
+
+// Call runs one handler directly, skipping routing and middleware. params
+// are name/value pairs for path parameters: Call(e, h.Get, req, "id", "1").
+//
+// A returned error is passed to e's HTTPErrorHandler, as it would be in a
+// real request, so the recorder holds the same status and body a client
+// would get.
+func Call(e *echo.Echo, h echo.HandlerFunc, req *http.Request, params ...string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	var names, values []string
+	for i := 0; i+1 < len(params); i += 2 {
+		names = append(names, params[i])
+		values = append(values, params[i+1])
+	}
+	c.SetParamNames(names...)
+	c.SetParamValues(values...)
+
+	if err := h(c); err != nil {
+		e.HTTPErrorHandler(err, c)
+	}
+	return rec
+}
+
+// Middleware runs mw in front of a handler that answers 200 "ok", and
+// reports whether that handler was reached.
+func Middleware(e *echo.Echo, mw echo.MiddlewareFunc, req *http.Request) (rec *httptest.ResponseRecorder, reached bool) {
+	next := func(c echo.Context) error {
+		reached = true
+		return c.String(http.StatusOK, "ok")
+	}
+	return Call(e, mw(next), req), reached
+}
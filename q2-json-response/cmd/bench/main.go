@@ -0,0 +1,153 @@
+// Command bench compares `go test -bench` output with a committed
+// baseline, so a regression in q2's handler benchmarks fails CI:
+//
+//	go test -run '^$' -bench . -benchmem ./handlers | go run ./cmd/bench -baseline cmd/bench/baseline.json
+//	go test -run '^$' -bench . -benchmem -count 5 ./handlers | go run ./cmd/bench -save cmd/bench/baseline.json
+//
+// The benchmarks are in handlers/*_bench_test.go. The input is echoed as
+// it is read, so the usual go test output still shows. With -baseline it
+// exits 1 when a benchmark got more than -tolerance slower or allocates
+// more than 1% more than before (allocs/op is an average, and pools make it
+// drift slightly). Single runs vary by 10-20%, hence the 25% default; with
+// -count the fastest run of each benchmark counts, which steadies it. The baseline is
+// only meaningful on the machine it was recorded on; record one per CI
+// runner type.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Result is one benchmark's numbers, as stored in a baseline file.
+type Result struct {
+	NsPerOp     int64 `json:"ns_per_op"`
+	BytesPerOp  int64 `json:"bytes_per_op"`
+	AllocsPerOp int64 `json:"allocs_per_op"`
+}
+
+// procsSuffix is the -GOMAXPROCS suffix go test appends to a benchmark
+// name; it is dropped so baselines work across core counts.
+var procsSuffix = regexp.MustCompile(`-\d+$`)
+
+func main() {
+	baseline := flag.String("baseline", "", "compare with the results in this file")
+	save := flag.String("save", "", "write the results to this file")
+	tolerance := flag.Float64("tolerance", 0.25, "slowdown against -baseline that counts as a regression")
+	flag.Parse()
+
+	results, order, err := parse(io.TeeReader(os.Stdin, os.Stdout))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *save != "" {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := os.WriteFile(*save, append(data, '\n'), 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *baseline != "" {
+		data, err := os.ReadFile(*baseline)
+		if err != nil {
+			log.Fatal(err)
+		}
+		var base map[string]Result
+		if err := json.Unmarshal(data, &base); err != nil {
+			log.Fatalf("%s: %v", *baseline, err)
+		}
+		if regressed := compare(order, results, base, *tolerance); regressed > 0 {
+			fmt.Printf("%d regression(s) against %s\n", regressed, *baseline)
+			os.Exit(1)
+		}
+	}
+}
+
+// parse reads go test -bench -benchmem output. It fails when go test
+// reported a failure or no benchmark ran, since the pipe hides go test's
+// exit status.
+func parse(r io.Reader) (map[string]Result, []string, error) {
+	results := map[string]Result{}
+	var order []string
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.HasPrefix(line, "FAIL") || strings.HasPrefix(line, "--- FAIL") {
+			return nil, nil, fmt.Errorf("go test failed: %s", line)
+		}
+
+		// BenchmarkGet/std  226243  5312 ns/op  6417 B/op  22 allocs/op
+		fields := strings.Fields(line)
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+		var res Result
+		for i := 2; i+1 < len(fields); i += 2 {
+			v, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%q: %v", line, err)
+			}
+			switch fields[i+1] {
+			case "ns/op":
+				res.NsPerOp = int64(v)
+			case "B/op":
+				res.BytesPerOp = int64(v)
+			case "allocs/op":
+				res.AllocsPerOp = int64(v)
+			}
+		}
+
+		name := procsSuffix.ReplaceAllString(fields[0], "")
+		prev, seen := results[name]
+		if !seen {
+			order = append(order, name)
+		}
+		if !seen || res.NsPerOp < prev.NsPerOp {
+			results[name] = res
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, nil, err
+	}
+	if len(results) == 0 {
+		return nil, nil, fmt.Errorf("no benchmark results in the input; run go test with -bench and -benchmem")
+	}
+	return results, order, nil
+}
+
+// compare prints each result against the baseline and returns how many
+// regressed: slower by more than tolerance, or allocating over 1% more often.
+func compare(order []string, results, base map[string]Result, tolerance float64) int {
+	fmt.Println()
+	regressed := 0
+	for _, key := range order {
+		r := results[key]
+		b, ok := base[key]
+		if !ok || b.NsPerOp == 0 {
+			fmt.Printf("%-24s no baseline\n", key)
+			continue
+		}
+
+		delta := float64(r.NsPerOp-b.NsPerOp) / float64(b.NsPerOp)
+		verdict := "ok"
+		if delta > tolerance || r.AllocsPerOp > b.AllocsPerOp+b.AllocsPerOp/100 {
+			verdict = "REGRESSION"
+			regressed++
+		}
+		fmt.Printf("%-24s %+6.1f%% ns/op  allocs %d -> %d  %s\n", key, delta*100, b.AllocsPerOp, r.AllocsPerOp, verdict)
+	}
+	return regressed
+}
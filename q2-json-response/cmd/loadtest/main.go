@@ -0,0 +1,248 @@
+// Command loadtest sends requests to a running server for a while and
+// reports throughput, status codes and latency percentiles:
+//
+//	go run . &
+//	go run ./cmd/loadtest -c 20 -d 15s -url 'http://127.0.0.1:3000/users?per_page=20'
+//	go run ./cmd/loadtest -rate 500 -d 30s -baseline loadtest.json
+//
+// -c workers send back to back; -rate caps them at that many requests per
+// second in total, for latency under a steady load rather than at
+// saturation. Works against any HTTP server, todo-app included; profile it
+// meanwhile through todo-app's /debug/pprof.
+//
+// With -baseline it exits 1 when p99 latency rose, or throughput fell, by
+// more than -tolerance; -save writes a new baseline.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Result is what one run measured, as stored in a baseline file.
+type Result struct {
+	URL         string `json:"url"`
+	Concurrency int    `json:"concurrency"`
+	Rate        int    `json:"rate"`
+
+	Requests int            `json:"requests"`
+	Errors   int            `json:"errors"` // transport errors, no status
+	Statuses map[string]int `json:"statuses"`
+	RPS      float64        `json:"rps"`
+	Latency  Latency        `json:"latency_ms"`
+}
+
+type Latency struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+// headers collects repeated -H flags.
+type headers []string
+
+func (h *headers) String() string     { return strings.Join(*h, ", ") }
+func (h *headers) Set(v string) error { *h = append(*h, v); return nil }
+
+func main() {
+	url := flag.String("url", "http://127.0.0.1:3000/users?per_page=20", "target URL")
+	method := flag.String("method", http.MethodGet, "HTTP method")
+	body := flag.String("body", "", "request body; sent as application/json")
+	concurrency := flag.Int("c", 10, "concurrent workers")
+	duration := flag.Duration("d", 10*time.Second, "how long to send for")
+	rate := flag.Int("rate", 0, "total requests per second; 0 sends as fast as the workers can")
+	timeout := flag.Duration("timeout", 5*time.Second, "per-request timeout")
+	baseline := flag.String("baseline", "", "compare with the result in this file")
+	save := flag.String("save", "", "write the result to this file")
+	tolerance := flag.Float64("tolerance", 0.2, "change against -baseline that counts as a regression")
+	var hdrs headers
+	flag.Var(&hdrs, "H", `request header "Name: value"; repeatable`)
+	flag.Parse()
+
+	client := &http.Client{
+		Timeout: *timeout,
+		// keep a connection per worker instead of opening new ones
+		Transport: &http.Transport{MaxIdleConnsPerHost: *concurrency},
+	}
+
+	newRequest := func() (*http.Request, error) {
+		req, err := http.NewRequest(*method, *url, strings.NewReader(*body))
+		if err != nil {
+			return nil, err
+		}
+		if *body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		for _, h := range hdrs {
+			name, value, ok := strings.Cut(h, ":")
+			if !ok {
+				return nil, fmt.Errorf("header %q is not Name: value", h)
+			}
+			req.Header.Set(strings.TrimSpace(name), strings.TrimSpace(value))
+		}
+		return req, nil
+	}
+	if _, err := newRequest(); err != nil {
+		log.Fatal(err)
+	}
+
+	// with -rate, workers take a ticket per request
+	var tickets <-chan time.Time
+	if *rate > 0 {
+		t := time.NewTicker(time.Second / time.Duration(*rate))
+		defer t.Stop()
+		tickets = t.C
+	}
+
+	fmt.Printf("%s %s for %s with %d workers\n", *method, *url, *duration, *concurrency)
+	deadline := time.Now().Add(*duration)
+	start := time.Now()
+
+	var (
+		mu        sync.Mutex
+		latencies []time.Duration
+		statuses  = map[string]int{}
+		errs      int
+		wg        sync.WaitGroup
+	)
+	for range *concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var mine []time.Duration
+			for time.Now().Before(deadline) {
+				if tickets != nil {
+					<-tickets
+				}
+				req, _ := newRequest()
+				t0 := time.Now()
+				res, err := client.Do(req)
+				if err == nil {
+					// read the whole body, as a real client would
+					_, _ = io.Copy(io.Discard, res.Body)
+					res.Body.Close()
+				}
+				elapsed := time.Since(t0)
+
+				mu.Lock()
+				if err != nil {
+					errs++
+				} else {
+					statuses[fmt.Sprint(res.StatusCode)]++
+				}
+				mu.Unlock()
+				mine = append(mine, elapsed)
+			}
+			mu.Lock()
+			latencies = append(latencies, mine...)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	slices.Sort(latencies)
+	r := Result{
+		URL:         *url,
+		Concurrency: *concurrency,
+		Rate:        *rate,
+		Requests:    len(latencies),
+		Errors:      errs,
+		Statuses:    statuses,
+		RPS:         float64(len(latencies)) / elapsed.Seconds(),
+		Latency: Latency{
+			P50: ms(percentile(latencies, 0.50)),
+			P90: ms(percentile(latencies, 0.90)),
+			P99: ms(percentile(latencies, 0.99)),
+			Max: ms(percentile(latencies, 1)),
+		},
+	}
+	report(r)
+
+	if *save != "" {
+		data, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := os.WriteFile(*save, append(data, '\n'), 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *baseline != "" {
+		data, err := os.ReadFile(*baseline)
+		if err != nil {
+			log.Fatal(err)
+		}
+		var base Result
+		if err := json.Unmarshal(data, &base); err != nil {
+			log.Fatalf("%s: %v", *baseline, err)
+		}
+		if !compare(r, base, *tolerance) {
+			os.Exit(1)
+		}
+	}
+}
+
+func report(r Result) {
+	codes := make([]string, 0, len(r.Statuses))
+	for code, n := range r.Statuses {
+		codes = append(codes, fmt.Sprintf("%s x%d", code, n))
+	}
+	slices.Sort(codes)
+
+	fmt.Printf("requests  %d (%.0f/s), %d errors\n", r.Requests, r.RPS, r.Errors)
+	fmt.Printf("statuses  %s\n", strings.Join(codes, ", "))
+	fmt.Printf("latency   p50 %.2fms  p90 %.2fms  p99 %.2fms  max %.2fms\n",
+		r.Latency.P50, r.Latency.P90, r.Latency.P99, r.Latency.Max)
+}
+
+// compare prints r against base and reports whether it is within
+// tolerance: p99 no more than that much slower, throughput no more than
+// that much lower.
+func compare(r, base Result, tolerance float64) bool {
+	ok := true
+	check := func(name string, got, want float64, higherIsWorse bool) {
+		if want == 0 {
+			return
+		}
+		delta := (got - want) / want
+		verdict := "ok"
+		if (higherIsWorse && delta > tolerance) || (!higherIsWorse && delta < -tolerance) {
+			verdict = "REGRESSION"
+			ok = false
+		}
+		fmt.Printf("%-4s %10.2f -> %10.2f  %+6.1f%%  %s\n", name, want, got, delta*100, verdict)
+	}
+
+	fmt.Println()
+	if r.URL != base.URL || r.Concurrency != base.Concurrency || r.Rate != base.Rate {
+		fmt.Printf("warning: baseline was -url %s -c %d -rate %d; compare runs with the same flags\n", base.URL, base.Concurrency, base.Rate)
+	}
+	check("p99", r.Latency.P99, base.Latency.P99, true)
+	check("rps", r.RPS, base.RPS, false)
+	return ok
+}
+
+// percentile expects sorted durations; q=1 is the maximum.
+func percentile(sorted []time.Duration, q float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(q * float64(len(sorted)-1))
+	return sorted[i]
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
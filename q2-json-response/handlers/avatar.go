@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/apierror"
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/response"
+	"github.com/jabeedhexanovamedia/json-res/store"
+	"github.com/labstack/echo/v4"
+)
+
+// maxAvatarSize caps the image itself; the route's BodyLimit adds headroom
+// for the multipart framing around it.
+const maxAvatarSize = 2 << 20 // 2 MiB
+
+// UploadAvatar handles POST /users/:id/avatar with a multipart form whose
+// "avatar" field holds a PNG, JPEG, GIF or WebP image.
+func (h *UserHandler) UploadAvatar(c echo.Context) error {
+	id, err := h.store.ParseID(c.Param("id"))
+	if err != nil {
+		return apierror.BadRequest("invalid user id")
+	}
+	_, err = h.store.Get(id)
+	if errors.Is(err, store.ErrUserNotFound) {
+		return apierror.Wrap(err, apierror.ErrNotFound, "user not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	fh, err := c.FormFile("avatar")
+	if err != nil {
+		return apierror.BadRequest(`multipart field "avatar" is required`)
+	}
+	if fh.Size > maxAvatarSize {
+		return payloadTooLarge("avatar must be at most 2 MiB")
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Size in the header comes from the client, so read at most one byte
+	// past the limit to be sure.
+	data, err := io.ReadAll(io.LimitReader(f, maxAvatarSize+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > maxAvatarSize {
+		return payloadTooLarge("avatar must be at most 2 MiB")
+	}
+
+	// The part's Content-Type is whatever the client claims; sniff the bytes
+	// instead so a script renamed to .png is still rejected.
+	contentType := http.DetectContentType(data)
+	if _, ok := store.AvatarExtensions[contentType]; !ok {
+		return unsupportedMediaType("avatar must be a PNG, JPEG, GIF or WebP image")
+	}
+
+	if err := h.avatars.Save(id, contentType, data); err != nil {
+		return err
+	}
+
+	return response.NoContent(c)
+}
+
+// GetAvatar handles GET /users/:id/avatar, serving the stored file with a
+// Content-Type taken from its extension.
+func (h *UserHandler) GetAvatar(c echo.Context) error {
+	id, err := h.store.ParseID(c.Param("id"))
+	if err != nil {
+		return apierror.BadRequest("invalid user id")
+	}
+
+	path, err := h.avatars.Path(id)
+	if errors.Is(err, store.ErrAvatarNotFound) {
+		return apierror.Wrap(err, apierror.ErrNotFound, "avatar not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	// uploaded content must never be reinterpreted as HTML by the browser
+	c.Response().Header().Set(echo.HeaderXContentTypeOptions, "nosniff")
+	return c.File(path)
+}
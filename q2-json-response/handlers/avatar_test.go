@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/testutil"
+	"github.com/jabeedhexanovamedia/json-res/models"
+)
+
+func TestUploadAvatarTooLarge(t *testing.T) {
+	e := newRouter(t)
+	signup := models.UserSignupRequest{Name: "Ada", Email: "ada@example.com", Password: "correct horse"}
+	rec := testutil.Serve(e, testutil.JSONRequest(t, http.MethodPost, "/users", signup))
+	testutil.AssertStatus(t, rec, http.StatusCreated)
+	id := testutil.DecodeJSON[models.UserResponse](t, rec).Id
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("avatar", "big.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write(make([]byte, maxAvatarSize+1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/users/"+id+"/avatar", &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	testutil.AssertError(t, testutil.Serve(e, req), http.StatusRequestEntityTooLarge, "request_entity_too_large")
+}
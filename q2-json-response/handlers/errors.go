@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/apierror"
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/response"
+	"github.com/labstack/echo/v4"
+)
+
+// Handlers return a *apierror.Error (apierror.BadRequest, NotFound,
+// Conflict, Validation, ...); HTTPErrorHandler renders it as the shared
+// error envelope:
+//
+//	{"error":{"code":"not_found","message":"user not found"}}
+//
+// The two below are q2's own kinds, for the avatar upload.
+
+func payloadTooLarge(message string) *apierror.Error {
+	return apierror.New(http.StatusRequestEntityTooLarge, message)
+}
+
+func unsupportedMediaType(message string) *apierror.Error {
+	return apierror.New(http.StatusUnsupportedMediaType, message)
+}
+
+// HTTPErrorHandler renders every error as response.Error, including the ones
+// Echo raises itself (404 unknown route, 405 wrong method, 413, ...).
+// Unknown errors become a 500 without leaking their text to the client.
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	apiErr := toAPIError(err)
+	if apiErr.Status >= http.StatusInternalServerError {
+		c.Logger().Error(err)
+	}
+
+	if sendErr := response.Error(c, apiErr.Status, apiErr.Code, apiErr.Message, apiErr.Fields); sendErr != nil {
+		c.Logger().Error(sendErr)
+	}
+}
+
+// toAPIError converts Echo's own errors, which apierror does not know
+// about, and leaves the rest to apierror.From.
+func toAPIError(err error) *apierror.Error {
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		message := http.StatusText(he.Code)
+		if m, ok := he.Message.(string); ok && m != "" {
+			message = m
+		}
+		return apierror.New(he.Code, strings.ToLower(message))
+	}
+	return apierror.From(err)
+}
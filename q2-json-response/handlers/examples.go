@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/jabeedhexanovamedia/json-res/models"
+	"github.com/labstack/echo/v4"
+)
+
+// Simple JSON Response: three ways to return a hardcoded user as JSON.
+
+// using map[string]interface{}
+func UserMap(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"id":   1,
+		"name": "john",
+	})
+}
+
+// using echo.Map
+func UserEchoMap(c echo.Context) error {
+	return c.JSON(http.StatusOK, echo.Map{
+		"id":   1,
+		"name": "John",
+	})
+}
+
+// using struct
+func UserStruct(c echo.Context) error {
+	return c.JSON(200, models.User{
+		Id:   "01890a5d-ac96-774b-bcce-b302099a8057",
+		Name: "john 2",
+	})
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/apierror"
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/pagination"
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/response"
+	"github.com/jabeedhexanovamedia/json-res/models"
+	"github.com/jabeedhexanovamedia/json-res/store"
+	"github.com/labstack/echo/v4"
+)
+
+// listOptions rejects out-of-range values with 400 instead of clamping
+// them, so client bugs show up early.
+var listOptions = pagination.Options{DefaultLimit: 20, MaxLimit: 100}
+
+// ListMeta is the page metadata plus the search and sort that produced it.
+type ListMeta struct {
+	response.Meta
+	Query string `json:"q,omitempty"`
+	Sort  string `json:"sort"`
+}
+
+// List handles GET /users?page=&per_page=&q=&sort=
+//
+//	page      1-based page number (default 1)
+//	per_page  page size (default 20, max 100)
+//	q         case-insensitive name search
+//	sort      created, name or email; prefix with - for descending
+func (h *UserHandler) List(c echo.Context) error {
+	page, err := pagination.Parse(c.QueryParams(), listOptions)
+	if err != nil {
+		return apierror.BadRequest(err.Error())
+	}
+	if page.Cursor != "" {
+		return apierror.BadRequest("cursor is not supported here; use page and per_page")
+	}
+
+	sortBy := c.QueryParam("sort")
+	if sortBy == "" {
+		sortBy = "created"
+	}
+	if !slices.Contains(store.SortFields, strings.TrimPrefix(sortBy, "-")) {
+		return apierror.BadRequest("sort must be one of " + strings.Join(store.SortFields, ", ") + " (prefix - for descending)")
+	}
+
+	q := strings.TrimSpace(c.QueryParam("q"))
+
+	users, total := h.store.List(store.ListOptions{
+		Query:  q,
+		Sort:   sortBy,
+		Offset: page.Offset,
+		Limit:  page.Limit,
+	})
+
+	data := make([]models.UserResponse, 0, len(users))
+	for _, u := range users {
+		data = append(data, models.ToUserResponse(u))
+	}
+
+	return response.Paginated(c, data, ListMeta{
+		Meta:  page.Meta(total),
+		Query: q,
+		Sort:  sortBy,
+	})
+}
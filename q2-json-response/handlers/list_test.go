@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/testutil"
+	"github.com/jabeedhexanovamedia/json-res/store"
+	"github.com/labstack/echo/v4"
+)
+
+func TestListPagination(t *testing.T) {
+	e := newRouter(t)
+
+	tests := []struct {
+		target string
+		want   int
+	}{
+		{"/users?page=2&per_page=20", http.StatusOK},
+		{"/users?page=0", http.StatusBadRequest},
+		{"/users?per_page=101", http.StatusBadRequest},
+		// (page-1)*per_page would overflow into a negative offset
+		{"/users?page=" + strconv.Itoa(math.MaxInt) + "&per_page=20", http.StatusBadRequest},
+		{"/users?page=" + strconv.Itoa(math.MaxInt/20+1) + "&per_page=20", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			rec := testutil.Serve(e, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			testutil.AssertStatus(t, rec, tt.want)
+		})
+	}
+}
+
+// newRouter is q2's full router on an empty user store.
+func newRouter(t *testing.T) *echo.Echo {
+	t.Helper()
+	avatars, err := store.NewAvatarStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewRouter(store.NewUserStore(nil), avatars)
+}
@@ -0,0 +1,13 @@
+package handlers
+
+import "golang.org/x/crypto/bcrypt"
+
+// hashPassword hashes with bcrypt, which salts every hash and is slow on
+// purpose, so a leaked store cannot be brute-forced cheaply.
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/deprecation"
+	sharedmw "github.com/jabeedhexanovamedia/go-echo-practice/pkg/middleware"
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/validation"
+	"github.com/jabeedhexanovamedia/json-res/store"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// NewRouter wires validator, error handler and every q2 route onto a new
+// Echo instance.
+func NewRouter(s *store.UserStore, avatars *store.AvatarStore) *echo.Echo {
+	e := echo.New()
+	e.Validator = validation.New()
+	e.HTTPErrorHandler = HTTPErrorHandler
+
+	// requireJSON guards routes that Bind a JSON body
+	requireJSON := echo.WrapMiddleware(sharedmw.RequireContentType(echo.MIMEApplicationJSON))
+
+	// /users1 and /users2 predate the user store; they answer with
+	// Deprecation and Sunset headers until they are removed
+	deprecated := echo.WrapMiddleware(deprecation.Middleware(deprecation.Policy{
+		Since:     time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC),
+		Sunset:    time.Date(2027, 6, 30, 0, 0, 0, 0, time.UTC),
+		Successor: "/users",
+	}))
+
+	e.GET("/users1", UserMap, deprecated)
+	e.GET("/user", UserEchoMap)
+	e.GET("/users2", UserStruct, deprecated)
+
+	users := NewUserHandler(s, avatars)
+	e.GET("/users", users.List)
+	e.GET("/users/stream", users.Stream)
+	e.POST("/users", users.Create, requireJSON)
+	e.GET("/users/:id", users.Get)
+	e.PUT("/users/:id", users.Update, requireJSON)
+	e.DELETE("/users/:id", users.Delete)
+
+	// BodyLimit stops oversized uploads before the multipart form is parsed
+	e.POST("/users/:id/avatar", users.UploadAvatar, middleware.BodyLimit("3M"), echo.WrapMiddleware(sharedmw.RequireContentType(echo.MIMEMultipartForm)))
+	e.GET("/users/:id/avatar", users.GetAvatar)
+
+	return e
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	gojson "github.com/goccy/go-json"
+	"github.com/labstack/echo/v4"
+)
+
+// GoJSONSerializer is a drop-in echo.JSONSerializer backed by goccy/go-json,
+// which is API compatible with encoding/json but avoids most of its
+// reflection cost on hot paths like the user list.
+type GoJSONSerializer struct{}
+
+func (GoJSONSerializer) Serialize(c echo.Context, i interface{}, indent string) error {
+	enc := gojson.NewEncoder(c.Response())
+	if indent != "" {
+		enc.SetIndent("", indent)
+	}
+	return enc.Encode(i)
+}
+
+// Deserialize mirrors echo.DefaultJSONSerializer: malformed JSON and type
+// mismatches become 400s, so Bind behaves the same with either serializer.
+func (GoJSONSerializer) Deserialize(c echo.Context, i interface{}) error {
+	err := gojson.NewDecoder(c.Request().Body).Decode(i)
+	if ute, ok := err.(*gojson.UnmarshalTypeError); ok {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unmarshal type error: expected=%v, got=%v, field=%v, offset=%v", ute.Type, ute.Value, ute.Field, ute.Offset)).SetInternal(err)
+	} else if se, ok := err.(*gojson.SyntaxError); ok {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Syntax error: offset=%v, error=%v", se.Offset, se.Error())).SetInternal(err)
+	}
+	return err
+}
+
+// JSONSerializers are the values accepted for JSON_SERIALIZER.
+var JSONSerializers = map[string]echo.JSONSerializer{
+	"std":     echo.DefaultJSONSerializer{},
+	"go-json": GoJSONSerializer{},
+}
+
+// NewJSONSerializer returns the serializer registered under name; an empty
+// name selects encoding/json.
+func NewJSONSerializer(name string) (echo.JSONSerializer, error) {
+	if name == "" {
+		name = "std"
+	}
+	s, ok := JSONSerializers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown JSON serializer %q (want std or go-json)", name)
+	}
+	return s, nil
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/jabeedhexanovamedia/json-res/models"
+	"github.com/jabeedhexanovamedia/json-res/store"
+	"github.com/labstack/echo/v4"
+)
+
+// The fixture is large enough that encoding a page shows up next to the
+// store's sort and paging.
+const (
+	benchUsers   = 1000
+	benchPerPage = 100
+)
+
+// benchStore returns a store seeded with benchUsers users, and the first
+// one created.
+func benchStore(b *testing.B) (*store.UserStore, *store.AvatarStore, models.User) {
+	b.Helper()
+	avatars, err := store.NewAvatarStore(b.TempDir())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	s := store.NewUserStore(nil)
+	var first models.User
+	for i := range benchUsers {
+		u, err := s.Create(models.User{
+			Name:  fmt.Sprintf("User %d", i),
+			Email: fmt.Sprintf("user%d@example.com", i),
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+		if i == 0 {
+			first = u
+		}
+	}
+	return s, avatars, first
+}
+
+// eachSerializer runs fn as one sub-benchmark per JSONSerializers entry,
+// named after it, with a router that uses that serializer.
+func eachSerializer(b *testing.B, s *store.UserStore, avatars *store.AvatarStore, fn func(b *testing.B, e *echo.Echo)) {
+	names := make([]string, 0, len(JSONSerializers))
+	for name := range JSONSerializers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		b.Run(name, func(b *testing.B) {
+			e := NewRouter(s, avatars)
+			e.JSONSerializer = JSONSerializers[name]
+			b.ReportAllocs()
+			fn(b, e)
+		})
+	}
+}
+
+// benchGet sends GET target through the full router once per iteration.
+func benchGet(b *testing.B, e *echo.Echo, target string) {
+	for b.Loop() {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusOK {
+			b.Fatalf("GET %s = %d: %s", target, rec.Code, rec.Body)
+		}
+	}
+}
+
+// BenchmarkEncode times the serializer alone on a page of users.
+func BenchmarkEncode(b *testing.B) {
+	s, avatars, _ := benchStore(b)
+	page, _ := s.List(store.ListOptions{Limit: benchPerPage})
+	resp := make([]models.UserResponse, len(page))
+	for i, u := range page {
+		resp[i] = models.ToUserResponse(u)
+	}
+
+	eachSerializer(b, s, avatars, func(b *testing.B, e *echo.Echo) {
+		for b.Loop() {
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+			if err := e.JSONSerializer.Serialize(c, resp, ""); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkList is GET /users through the router, so it includes routing,
+// sorting and paging as well as encoding.
+func BenchmarkList(b *testing.B) {
+	s, avatars, _ := benchStore(b)
+	eachSerializer(b, s, avatars, func(b *testing.B, e *echo.Echo) {
+		benchGet(b, e, fmt.Sprintf("/users?per_page=%d", benchPerPage))
+	})
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jabeedhexanovamedia/json-res/models"
+	"github.com/jabeedhexanovamedia/json-res/store"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	mimeNDJSON = "application/x-ndjson"
+	// flushEvery trades latency for syscalls: the client sees data after at
+	// most this many records, and the server never buffers more than that.
+	flushEvery = 100
+	// batchTimeout replaces the server-wide WriteTimeout, which would cut a
+	// long export off after 10s: each batch gets its own deadline instead,
+	// so only a client that stops reading is disconnected.
+	batchTimeout = 30 * time.Second
+)
+
+// Stream handles GET /users/stream: every user as newline-delimited JSON,
+// one object per line, written as it is read from the store.
+func (h *UserHandler) Stream(c echo.Context) error {
+	ids := h.store.IDs()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, mimeNDJSON)
+	res.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(res)
+	extendDeadline := func() {
+		// not every ResponseWriter supports deadlines (httptest does not)
+		_ = rc.SetWriteDeadline(time.Now().Add(batchTimeout))
+	}
+	extendDeadline()
+
+	// once the status is sent, errors can only end the stream, not change it
+	enc := json.NewEncoder(res)
+	ctx := c.Request().Context()
+	for i, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil // client went away
+		}
+
+		u, err := h.store.Get(id)
+		if errors.Is(err, store.ErrUserNotFound) {
+			continue // deleted since the snapshot
+		}
+		if err != nil {
+			return err
+		}
+		if err := enc.Encode(models.ToUserResponse(u)); err != nil {
+			return err
+		}
+		if (i+1)%flushEvery == 0 {
+			res.Flush()
+			extendDeadline()
+		}
+	}
+	res.Flush()
+
+	return nil
+}
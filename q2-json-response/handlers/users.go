@@ -0,0 +1,138 @@
+// Package handlers is the HTTP layer of q2: route setup, handlers, request
+// validation and the error envelope.
+package handlers
+
+import (
+	"errors"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/apierror"
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/response"
+	"github.com/jabeedhexanovamedia/json-res/models"
+	"github.com/jabeedhexanovamedia/json-res/store"
+	"github.com/labstack/echo/v4"
+)
+
+// errEmailConflict is the 409 for a duplicate email, carrying the field so
+// clients can show it next to the email input.
+var errEmailConflict = apierror.Conflict("email already in use", map[string]string{"email": "is already taken"})
+
+type UserHandler struct {
+	store   *store.UserStore
+	avatars *store.AvatarStore
+}
+
+func NewUserHandler(s *store.UserStore, avatars *store.AvatarStore) *UserHandler {
+	return &UserHandler{store: s, avatars: avatars}
+}
+
+// 	Create an Echo API `POST /users` that:
+// - Accepts JSON request body
+// - Converts JSON → Go struct
+// - Stores it and returns the created user as JSON response
+
+func (h *UserHandler) Create(c echo.Context) error {
+
+	var userReq models.UserSignupRequest
+
+	// JSON → Go struct
+	if err := c.Bind(&userReq); err != nil {
+		return apierror.BadRequest("invalid request payload")
+	}
+
+	// already an apierror.Validation listing every bad field
+	if err := c.Validate(&userReq); err != nil {
+		return err
+	}
+
+	hash, err := hashPassword(userReq.Password)
+	if err != nil {
+		return err
+	}
+
+	user, err := h.store.Create(models.User{Name: userReq.Name, Email: userReq.Email, PasswordHash: hash})
+	if errors.Is(err, store.ErrEmailTaken) {
+		return errEmailConflict
+	}
+	if err != nil {
+		return err
+	}
+
+	// Go struct → JSON
+	return response.Created(c, models.ToUserResponse(user))
+
+}
+
+func (h *UserHandler) Get(c echo.Context) error {
+	id, err := h.store.ParseID(c.Param("id"))
+	if err != nil {
+		return apierror.BadRequest("invalid user id")
+	}
+
+	user, err := h.store.Get(id)
+	if errors.Is(err, store.ErrUserNotFound) {
+		return apierror.Wrap(err, apierror.ErrNotFound, "user not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	return response.OK(c, models.ToUserResponse(user))
+}
+
+func (h *UserHandler) Update(c echo.Context) error {
+	id, err := h.store.ParseID(c.Param("id"))
+	if err != nil {
+		return apierror.BadRequest("invalid user id")
+	}
+
+	var userReq models.UserUpdateRequest
+	if err := c.Bind(&userReq); err != nil {
+		return apierror.BadRequest("invalid request payload")
+	}
+
+	// already an apierror.Validation listing every bad field
+	if err := c.Validate(&userReq); err != nil {
+		return err
+	}
+
+	// an empty hash tells the store to keep the current one
+	var hash string
+	if userReq.Password != "" {
+		if hash, err = hashPassword(userReq.Password); err != nil {
+			return err
+		}
+	}
+
+	user, err := h.store.Update(id, models.User{Name: userReq.Name, Email: userReq.Email, PasswordHash: hash})
+	if errors.Is(err, store.ErrUserNotFound) {
+		return apierror.Wrap(err, apierror.ErrNotFound, "user not found")
+	}
+	if errors.Is(err, store.ErrEmailTaken) {
+		return errEmailConflict
+	}
+	if err != nil {
+		return err
+	}
+
+	return response.OK(c, models.ToUserResponse(user))
+}
+
+func (h *UserHandler) Delete(c echo.Context) error {
+	id, err := h.store.ParseID(c.Param("id"))
+	if err != nil {
+		return apierror.BadRequest("invalid user id")
+	}
+
+	err = h.store.Delete(id)
+	if errors.Is(err, store.ErrUserNotFound) {
+		return apierror.Wrap(err, apierror.ErrNotFound, "user not found")
+	}
+	if err != nil {
+		return err
+	}
+	if err := h.avatars.Delete(id); err != nil {
+		return err
+	}
+
+	return response.NoContent(c)
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// The hot read paths, once per serializer. POST /users is left out: bcrypt
+// is meant to be slow and would hide everything else.
+
+func BenchmarkGet(b *testing.B) {
+	s, avatars, first := benchStore(b)
+	eachSerializer(b, s, avatars, func(b *testing.B, e *echo.Echo) {
+		benchGet(b, e, "/users/"+first.Id)
+	})
+}
+
+func BenchmarkSearch(b *testing.B) {
+	s, avatars, _ := benchStore(b)
+	eachSerializer(b, s, avatars, func(b *testing.B, e *echo.Echo) {
+		benchGet(b, e, fmt.Sprintf("/users?per_page=%d&q=user+1&sort=name", benchPerPage))
+	})
+}
+
+func BenchmarkStream(b *testing.B) {
+	s, avatars, _ := benchStore(b)
+	eachSerializer(b, s, avatars, func(b *testing.B, e *echo.Echo) {
+		benchGet(b, e, "/users/stream")
+	})
+}
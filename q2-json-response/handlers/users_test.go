@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/testutil"
+	"github.com/jabeedhexanovamedia/json-res/models"
+)
+
+func TestCreateAndGet(t *testing.T) {
+	e := newRouter(t)
+
+	signup := models.UserSignupRequest{Name: "Ada", Email: "ada@example.com", Password: "correct horse"}
+	rec := testutil.Serve(e, testutil.JSONRequest(t, http.MethodPost, "/users", signup))
+	testutil.AssertStatus(t, rec, http.StatusCreated)
+	created := testutil.DecodeJSON[models.UserResponse](t, rec)
+	if created.Id == "" || created.Name != "Ada" || created.Email != "ada@example.com" {
+		t.Fatalf("created = %+v", created)
+	}
+	if strings.Contains(rec.Body.String(), "password") {
+		t.Errorf("response leaks the password: %s", rec.Body)
+	}
+
+	rec = testutil.Serve(e, httptest.NewRequest(http.MethodGet, "/users/"+created.Id, nil))
+	testutil.AssertJSON(t, rec, created)
+}
+
+func TestCreateErrors(t *testing.T) {
+	e := newRouter(t)
+	ada := models.UserSignupRequest{Name: "Ada", Email: "ada@example.com", Password: "correct horse"}
+	testutil.AssertStatus(t, testutil.Serve(e, testutil.JSONRequest(t, http.MethodPost, "/users", ada)), http.StatusCreated)
+
+	t.Run("duplicate email", func(t *testing.T) {
+		rec := testutil.Serve(e, testutil.JSONRequest(t, http.MethodPost, "/users", ada))
+		body := testutil.AssertError(t, rec, http.StatusConflict, "conflict")
+		if body.Fields["email"] == "" {
+			t.Errorf("fields = %v, want an email entry", body.Fields)
+		}
+	})
+
+	t.Run("invalid fields", func(t *testing.T) {
+		bad := models.UserSignupRequest{Name: "A", Email: "not-an-email", Password: "short"}
+		rec := testutil.Serve(e, testutil.JSONRequest(t, http.MethodPost, "/users", bad))
+		body := testutil.AssertError(t, rec, http.StatusBadRequest, "validation_failed")
+		for _, f := range []string{"name", "email", "password"} {
+			if body.Fields[f] == "" {
+				t.Errorf("fields = %v, want an entry for %s", body.Fields, f)
+			}
+		}
+	})
+
+	t.Run("password over 72 bytes", func(t *testing.T) {
+		// 40 characters, but 80 bytes: more than bcrypt takes
+		long := models.UserSignupRequest{Name: "Bo", Email: "bo@example.com", Password: strings.Repeat("é", 40)}
+		rec := testutil.Serve(e, testutil.JSONRequest(t, http.MethodPost, "/users", long))
+		body := testutil.AssertError(t, rec, http.StatusBadRequest, "validation_failed")
+		if got := body.Fields["password"]; got != "must be at most 72 bytes" {
+			t.Errorf("password field = %q", got)
+		}
+	})
+
+	t.Run("multi-byte password within 72 bytes", func(t *testing.T) {
+		ok := models.UserSignupRequest{Name: "Cy", Email: "cy@example.com", Password: strings.Repeat("é", 36)}
+		testutil.AssertStatus(t, testutil.Serve(e, testutil.JSONRequest(t, http.MethodPost, "/users", ok)), http.StatusCreated)
+	})
+
+	t.Run("not JSON", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader("name=Ada"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		testutil.AssertError(t, testutil.Serve(e, req), http.StatusUnsupportedMediaType, "unsupported_media_type")
+	})
+}
+
+func TestGetErrors(t *testing.T) {
+	e := newRouter(t)
+	tests := []struct {
+		target string
+		status int
+		code   string
+	}{
+		{"/users/nope", http.StatusBadRequest, "bad_request"},
+		{"/nowhere", http.StatusNotFound, "not_found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			testutil.AssertError(t, testutil.Serve(e, httptest.NewRequest(http.MethodGet, tt.target, nil)), tt.status, tt.code)
+		})
+	}
+}
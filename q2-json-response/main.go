@@ -1,66 +1,67 @@
 package main
 
 import (
-	"net/http"
+	"context"
+	"log"
 
-	"github.com/labstack/echo/v4"
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/idgen"
+	"github.com/jabeedhexanovamedia/json-res/handlers"
+	"github.com/jabeedhexanovamedia/json-res/store"
 )
 
-type User struct {
-	Id   int    `json:"id"`
-	Name string `json:"name"`
-}
-type UserSignupRequest struct {
-	Name  string `json:"name,omitempty"`
-	Email string `json:"email"`
-}
-
 // Simple JSON Response: Create GET /user that returns a hardcoded user JSON: { "id":1, "name":"John" }.
+// The routes live in handlers/, the model and DTOs in models/ and the
+// in-memory store in store/; main only wires them together.
 func main() {
-	e := echo.New()
-
-	e.GET("/users", func(c echo.Context) error {
-		return c.JSON(http.StatusOK, map[string]interface{}{
-			"id":   1,
-			"name": "john",
-		})
-	})
-
-	// using echo.Map
-	e.GET("/user", func(c echo.Context) error {
-		return c.JSON(http.StatusOK, echo.Map{
-			"id":   1,
-			"name": "John",
-		})
-	})
+	// uploaded avatars are written under AVATAR_DIR (default ./uploads/avatars)
+	dir, err := config.GetEnv("AVATAR_DIR", "uploads/avatars")
+	if err != nil {
+		log.Fatal(err)
+	}
+	avatars, err := store.NewAvatarStore(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	// using struct
-	e.GET("/users2", func(C echo.Context) error {
-		return C.JSON(200, User{
-			Id:   2,
-			Name: "john 2",
-		})
-	})
+	// ID_FORMAT=ulid issues ULIDs instead of UUIDv7 user IDs
+	format, err := config.GetEnv("ID_FORMAT", "uuidv7")
+	if err != nil {
+		log.Fatal(err)
+	}
+	ids, err := idgen.NewGenerator(format)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	// 	Create an Echo API `POST /users` that:
-	// - Accepts JSON request body
-	// - Converts JSON → Go struct
-	// - Returns the same data as JSON response
+	e := handlers.NewRouter(store.NewUserStore(ids), avatars)
 
-	e.POST("/users", func(c echo.Context) error {
+	// JSON_SERIALIZER=go-json swaps encoding/json for goccy/go-json
+	name, err := config.GetEnv("JSON_SERIALIZER", "std")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if e.JSONSerializer, err = handlers.NewJSONSerializer(name); err != nil {
+		log.Fatal(err)
+	}
 
-		var userReq UserSignupRequest
+	// defaults to 127.0.0.1:3000; set BIND_ADDR=:: inside containers to
+	// serve IPv4 and IPv6
+	srv, err := config.LoadServer(config.DefaultServer("3000"))
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
 
-		// JSON → Go struct
-		if err := c.Bind(&userReq); err != nil {
-			return c.JSON(http.StatusBadRequest, echo.Map{
-				"message": "invalid request payload",
-			})
-		}
+	// e.Start would bind "tcp" itself; srv.Listen honours SERVER_NETWORK,
+	// BIND=unix:// and socket activation, and e.Start serves on e.Listener
+	ln, err := srv.Listen(context.Background())
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	e.Listener = ln
 
-		// Go struct → JSON
-		return c.JSON(200, userReq)
+	// timeouts for the underlying http.Server used by e.Start
+	srv.Apply(e.Server)
 
-	})
-	e.Logger.Fatal(e.Start("127.0.0.1:3000"))
+	e.Logger.Fatal(e.Start(srv.Addr()))
 }
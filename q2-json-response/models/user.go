@@ -0,0 +1,38 @@
+// Package models holds the q2 domain model and its request/response DTOs.
+package models
+
+// User is the stored model. PasswordHash is tagged json:"-" as a second
+// line of defence, but responses go through UserResponse anyway.
+type User struct {
+	Id           string `json:"id"`
+	Name         string `json:"name"`
+	Email        string `json:"email,omitempty"`
+	PasswordHash string `json:"-"`
+}
+
+// bcrypt refuses passwords over 72 bytes, so they are rejected here as a
+// validation error. max_bytes counts bytes; max would count characters,
+// and 40 "é"s are 80 bytes.
+type UserSignupRequest struct {
+	Name     string `json:"name,omitempty" validate:"required,min=2,max=50"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=8,max_bytes=72"`
+}
+
+// UserUpdateRequest keeps the current password when Password is empty.
+type UserUpdateRequest struct {
+	Name     string `json:"name,omitempty" validate:"required,min=2,max=50"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password,omitempty" validate:"omitempty,min=8,max_bytes=72"`
+}
+
+// UserResponse is what clients see: never the password or its hash.
+type UserResponse struct {
+	Id    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func ToUserResponse(u User) UserResponse {
+	return UserResponse{Id: u.Id, Name: u.Name, Email: u.Email}
+}
@@ -0,0 +1,102 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var ErrAvatarNotFound = errors.New("avatar not found")
+
+// AvatarExtensions maps the accepted image MIME types to the extension the
+// file is stored with; serving then derives Content-Type from the extension.
+var AvatarExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// AvatarStore keeps one avatar file per user in dir, named <user id><ext>.
+// The lock serialises writers for the same directory so a replace (remove
+// old extension, rename new file in) is never observed half-done.
+type AvatarStore struct {
+	mu  sync.RWMutex
+	dir string
+}
+
+func NewAvatarStore(dir string) (*AvatarStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create avatar dir: %w", err)
+	}
+	return &AvatarStore{dir: dir}, nil
+}
+
+// Save stores data as the avatar of userID, replacing any previous one.
+// The file is written to a temp name first and renamed into place, so a
+// failed upload never leaves a truncated avatar behind.
+func (s *AvatarStore) Save(userID, contentType string, data []byte) error {
+	ext, ok := AvatarExtensions[contentType]
+	if !ok {
+		return fmt.Errorf("unsupported avatar type %q", contentType)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.removeLocked(userID); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(s.dir, userID+ext))
+}
+
+// Path returns the file holding userID's avatar, or ErrAvatarNotFound.
+func (s *AvatarStore) Path(userID string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, ext := range AvatarExtensions {
+		p := filepath.Join(s.dir, userID+ext)
+		if _, err := os.Stat(p); err == nil {
+			return p, nil
+		}
+	}
+	return "", ErrAvatarNotFound
+}
+
+// Delete removes userID's avatar; a user without one is not an error.
+func (s *AvatarStore) Delete(userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.removeLocked(userID)
+}
+
+// removeLocked deletes every stored extension for userID, so a PNG replaced
+// by a JPEG does not linger. Callers must hold the write lock.
+func (s *AvatarStore) removeLocked(userID string) error {
+	for _, ext := range AvatarExtensions {
+		err := os.Remove(filepath.Join(s.dir, userID+ext))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
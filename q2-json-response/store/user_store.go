@@ -0,0 +1,181 @@
+// Package store is the in-memory persistence layer for q2.
+package store
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/idgen"
+	"github.com/jabeedhexanovamedia/json-res/models"
+)
+
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrEmailTaken   = errors.New("email already in use")
+)
+
+// UserStore is an in-memory user store. Echo serves each request on its own
+// goroutine, so every method takes the lock: RLock for reads, Lock for writes.
+type UserStore struct {
+	mu    sync.RWMutex
+	users map[string]models.User
+	ids   idgen.Generator
+}
+
+// NewUserStore assigns IDs from ids; nil means UUIDv7.
+func NewUserStore(ids idgen.Generator) *UserStore {
+	if ids == nil {
+		ids = idgen.UUIDv7{}
+	}
+	return &UserStore{
+		users: make(map[string]models.User),
+		ids:   ids,
+	}
+}
+
+// ParseID validates an ID from a client against the store's ID format.
+func (s *UserStore) ParseID(id string) (string, error) {
+	return s.ids.Parse(id)
+}
+
+func (s *UserStore) Create(u models.User) (models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// checked under the same lock as the insert, so two concurrent signups
+	// with one email cannot both pass
+	if s.emailTaken(u.Email, "") {
+		return models.User{}, ErrEmailTaken
+	}
+
+	u.Id = s.ids.New()
+	s.users[u.Id] = u
+
+	return u, nil
+}
+
+func (s *UserStore) Get(id string) (models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	u, ok := s.users[id]
+	if !ok {
+		return models.User{}, ErrUserNotFound
+	}
+	return u, nil
+}
+
+// ListOptions filters, sorts and pages List results.
+type ListOptions struct {
+	// Query matches names case-insensitively by substring
+	Query string
+	// Sort is a field name, "-" prefixed for descending: name, -email, created
+	Sort   string
+	Offset int
+	Limit  int
+}
+
+// SortFields are the values ListOptions.Sort accepts (with optional "-").
+var SortFields = []string{"created", "name", "email"}
+
+// List returns one page of users matching opts, plus the total number of
+// matches before paging. The default order is creation (UUIDv7 ID) order.
+func (s *UserStore) List(opts ListOptions) ([]models.User, int) {
+	s.mu.RLock()
+	users := make([]models.User, 0, len(s.users))
+	q := strings.ToLower(opts.Query)
+	for _, u := range s.users {
+		if q == "" || strings.Contains(strings.ToLower(u.Name), q) {
+			users = append(users, u)
+		}
+	}
+	s.mu.RUnlock()
+
+	field, desc := strings.TrimPrefix(opts.Sort, "-"), strings.HasPrefix(opts.Sort, "-")
+	sort.SliceStable(users, func(i, j int) bool {
+		a, b := users[i], users[j]
+		if desc {
+			a, b = b, a
+		}
+		switch field {
+		case "name":
+			if !strings.EqualFold(a.Name, b.Name) {
+				return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+			}
+		case "email":
+			if !strings.EqualFold(a.Email, b.Email) {
+				return strings.ToLower(a.Email) < strings.ToLower(b.Email)
+			}
+		}
+		return a.Id < b.Id
+	})
+
+	total := len(users)
+	start := min(max(opts.Offset, 0), total)
+	end := total
+	if opts.Limit > 0 {
+		end = min(start+opts.Limit, total)
+	}
+
+	return users[start:end], total
+}
+
+// IDs returns every user ID in creation order. Streaming callers snapshot
+// the IDs and Get each user in turn, instead of holding the read lock (and
+// blocking writers) for as long as a slow client takes to download.
+func (s *UserStore) IDs() []string {
+	s.mu.RLock()
+	ids := make([]string, 0, len(s.users))
+	for id := range s.users {
+		ids = append(ids, id)
+	}
+	s.mu.RUnlock()
+
+	sort.Strings(ids)
+	return ids
+}
+
+func (s *UserStore) Update(id string, u models.User) (models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.users[id]
+	if !ok {
+		return models.User{}, ErrUserNotFound
+	}
+	if s.emailTaken(u.Email, id) {
+		return models.User{}, ErrEmailTaken
+	}
+	if u.PasswordHash == "" {
+		u.PasswordHash = existing.PasswordHash
+	}
+	u.Id = id
+	s.users[id] = u
+
+	return u, nil
+}
+
+func (s *UserStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[id]; !ok {
+		return ErrUserNotFound
+	}
+	delete(s.users, id)
+
+	return nil
+}
+
+// emailTaken reports whether another user (not exceptID) has email, compared
+// case-insensitively. Callers must hold the lock.
+func (s *UserStore) emailTaken(email, exceptID string) bool {
+	for id, u := range s.users {
+		if id != exceptID && strings.EqualFold(u.Email, email) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package store
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/jabeedhexanovamedia/json-res/models"
+)
+
+func TestListPaging(t *testing.T) {
+	s := NewUserStore(nil)
+	for i := range 5 {
+		if _, err := s.Create(models.User{Name: fmt.Sprintf("User %d", i), Email: fmt.Sprintf("u%d@example.com", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		opts  ListOptions
+		wantN int
+	}{
+		{name: "first page", opts: ListOptions{Offset: 0, Limit: 2}, wantN: 2},
+		{name: "last partial page", opts: ListOptions{Offset: 4, Limit: 2}, wantN: 1},
+		{name: "past the end", opts: ListOptions{Offset: 10, Limit: 2}, wantN: 0},
+		{name: "no limit", opts: ListOptions{}, wantN: 5},
+		{name: "negative offset", opts: ListOptions{Offset: -20, Limit: 2}, wantN: 2},
+		{name: "huge offset", opts: ListOptions{Offset: math.MaxInt, Limit: 20}, wantN: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, total := s.List(tt.opts)
+			if len(users) != tt.wantN || total != 5 {
+				t.Errorf("List(%+v) = %d users of %d, want %d of 5", tt.opts, len(users), total, tt.wantN)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Enum is implemented by string types that only take a fixed set of
+// values. The binder rejects anything else and lists what is allowed.
+type Enum interface {
+	Values() []string
+}
+
+// timeLayouts are tried in order for time.Time fields. Values without a
+// zone are taken as UTC.
+var timeLayouts = []string{
+	time.RFC3339,          // 2024-05-01T10:00:00Z, 2024-05-01T10:00:00.5+02:00
+	"2006-01-02T15:04:05", // no zone
+	"2006-01-02T15:04",    // <input type="datetime-local">
+	time.DateOnly,         // 2024-05-01
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// BindError is the 400 body for a value that does not fit its field:
+// which input it was, where it came from, and what was wrong with it.
+type BindError struct {
+	Message string `json:"message"`
+	Field   string `json:"field"`
+	Source  string `json:"source"` // param, query or form
+}
+
+// Binder replaces echo.DefaultBinder for path, query and form values, so
+// they understand more types and report errors per field. JSON and XML
+// bodies still go through the embedded DefaultBinder.
+type Binder struct {
+	echo.DefaultBinder
+}
+
+// Bind fills i from path params, then query params (for every method, not
+// only GET), then the body. A later source overwrites an earlier one.
+func (b *Binder) Bind(i any, c echo.Context) error {
+	params := map[string][]string{}
+	for idx, name := range c.ParamNames() {
+		params[name] = []string{c.ParamValues()[idx]}
+	}
+	if err := bindValues(i, params, "param"); err != nil {
+		return err
+	}
+	if err := bindValues(i, c.QueryParams(), "query"); err != nil {
+		return err
+	}
+
+	ct := c.Request().Header.Get(echo.HeaderContentType)
+	if strings.HasPrefix(ct, echo.MIMEApplicationForm) || strings.HasPrefix(ct, echo.MIMEMultipartForm) {
+		form, err := c.FormParams()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid form body").SetInternal(err)
+		}
+		return bindValues(i, form, "form")
+	}
+	return b.BindBody(c, i)
+}
+
+// bindValues sets every field of the struct i points to that has a tag
+// named tag with a value in data.
+func bindValues(i any, data map[string][]string, tag string) error {
+	v := reflect.ValueOf(i)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	v = v.Elem()
+
+	for idx := range v.NumField() {
+		f := v.Type().Field(idx)
+		name := f.Tag.Get(tag)
+		if name == "" || name == "-" {
+			continue
+		}
+		values, ok := data[name]
+		if !ok || !slices.ContainsFunc(values, func(s string) bool { return s != "" }) {
+			continue // absent or blank: keep the default
+		}
+		if err := setField(v.Field(idx), values); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, BindError{
+				Message: name + " " + err.Error(),
+				Field:   name,
+				Source:  tag,
+			}).SetInternal(err)
+		}
+	}
+	return nil
+}
+
+// setField converts values into fv. Slices take repeated values and
+// comma-separated ones alike: ?tag=a&tag=b,c gives [a b c].
+func setField(fv reflect.Value, values []string) error {
+	if fv.Kind() == reflect.Pointer {
+		ptr := reflect.New(fv.Type().Elem())
+		if err := setField(ptr.Elem(), values); err != nil {
+			return err
+		}
+		fv.Set(ptr)
+		return nil
+	}
+
+	if fv.Kind() == reflect.Slice {
+		var parts []string
+		for _, v := range values {
+			for _, p := range strings.Split(v, ",") {
+				if p = strings.TrimSpace(p); p != "" {
+					parts = append(parts, p)
+				}
+			}
+		}
+		s := reflect.MakeSlice(fv.Type(), len(parts), len(parts))
+		for i, p := range parts {
+			if err := setScalar(s.Index(i), p); err != nil {
+				return fmt.Errorf("item %d: %w", i+1, err)
+			}
+		}
+		fv.Set(s)
+		return nil
+	}
+
+	return setScalar(fv, strings.TrimSpace(values[0]))
+}
+
+func setScalar(fv reflect.Value, s string) error {
+	if fv.Type() == timeType {
+		t, err := parseTime(s)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	if e, ok := fv.Addr().Interface().(Enum); ok && fv.Kind() == reflect.String {
+		allowed := e.Values()
+		if !slices.Contains(allowed, s) {
+			return fmt.Errorf("must be one of %s, got %q", strings.Join(allowed, ", "), s)
+		}
+		fv.SetString(s)
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		if err != nil {
+			return numError(err, "an integer")
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		if err != nil {
+			return numError(err, "a non-negative integer")
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return numError(err, "a number")
+		}
+		fv.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return errors.New("must be true or false")
+		}
+		fv.SetBool(b)
+	default:
+		// a struct field of a type nobody taught the binder: a bug in the
+		// code, not in the request
+		panic(fmt.Sprintf("binder: unsupported field type %s", fv.Type()))
+	}
+	return nil
+}
+
+func numError(err error, want string) error {
+	if errors.Is(err, strconv.ErrRange) {
+		return errors.New("is out of range")
+	}
+	return errors.New("must be " + want)
+}
+
+// parseTime accepts the layouts in timeLayouts, or Unix seconds.
+func parseTime(s string) (time.Time, error) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(n, 0).UTC(), nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("must be a time like 2024-05-01T10:00:00Z, 2024-05-01T10:00, 2024-05-01 or Unix seconds, got %q", s)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+type Status string
+
+func (Status) Values() []string { return []string{"open", "closed", "archived"} }
+
+type SortOrder string
+
+func (SortOrder) Values() []string { return []string{"asc", "desc"} }
+
+// EventQuery is GET /calendars/:id/events. Every field is converted by the
+// custom binder: times in several formats, comma-separated lists, enums.
+type EventQuery struct {
+	CalendarID int       `param:"id" json:"calendar_id"`
+	From       time.Time `query:"from" json:"from"`
+	To         time.Time `query:"to" json:"to"`
+	Status     []Status  `query:"status" json:"status"` // ?status=open,closed
+	Tags       []string  `query:"tags" json:"tags"`
+	IDs        []int     `query:"ids" json:"ids,omitempty"`
+	Sort       SortOrder `query:"sort" json:"sort"`
+	Limit      int       `query:"limit" json:"limit"`
+}
+
+// EventForm is an HTML form post; "at" comes from <input type="datetime-local">.
+type EventForm struct {
+	Title    string     `form:"title" json:"title"`
+	At       time.Time  `form:"at" json:"at"`
+	Status   Status     `form:"status" json:"status"`
+	Reminder *time.Time `form:"reminder" json:"reminder,omitempty"`
+}
+
+// Custom Binder: e.Binder replaced by one that understands time.Time in
+// several formats, comma-separated slices and enum types in path, query
+// and form values, and answers 400 naming the field that was wrong.
+func main() {
+	e := echo.New()
+	e.Use(middleware.Logger())
+	e.Binder = &Binder{}
+
+	e.GET("/calendars/:id/events", func(c echo.Context) error {
+		q := EventQuery{Sort: "asc", Limit: 20} // defaults, kept unless sent
+		if err := c.Bind(&q); err != nil {
+			return err
+		}
+		if !q.From.IsZero() && !q.To.IsZero() && q.To.Before(q.From) {
+			return echo.NewHTTPError(http.StatusBadRequest, BindError{Message: "to must not be before from", Field: "to", Source: "query"})
+		}
+		return c.JSON(http.StatusOK, q)
+	})
+
+	e.POST("/events", func(c echo.Context) error {
+		f := EventForm{Status: "open"}
+		if err := c.Bind(&f); err != nil {
+			return err
+		}
+		return c.JSON(http.StatusCreated, f)
+	})
+
+	srv, err := config.LoadServer(config.DefaultServer("3000"))
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	srv.Apply(e.Server)
+
+	if err := e.Start(srv.Addr()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		e.Logger.Fatal(err)
+	}
+}
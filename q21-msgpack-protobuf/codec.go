@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	MIMEMsgpack  = "application/msgpack"
+	MIMEProtobuf = "application/x-protobuf"
+)
+
+// Codec turns values into one wire format and back.
+type Codec interface {
+	MediaType() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// Codecs is the set of formats the server speaks, keyed by media type.
+// The first one registered is the default for Accept: */* and for
+// requests without an Accept header.
+type Codecs struct {
+	byType map[string]Codec
+	order  []string
+}
+
+func NewCodecs(codecs ...Codec) *Codecs {
+	r := &Codecs{byType: map[string]Codec{}}
+	for _, c := range codecs {
+		r.Register(c)
+	}
+	return r
+}
+
+// Register adds a codec, or replaces the one for the same media type.
+func (r *Codecs) Register(c Codec) {
+	if _, ok := r.byType[c.MediaType()]; !ok {
+		r.order = append(r.order, c.MediaType())
+	}
+	r.byType[c.MediaType()] = c
+}
+
+// ForContentType picks the codec for a request body.
+func (r *Codecs) ForContentType(header string) (Codec, bool) {
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return nil, false
+	}
+	c, ok := r.byType[mediaType]
+	return c, ok
+}
+
+// Negotiate picks the codec for a response from the Accept header: the
+// supported type with the highest q, ties going to the earlier entry.
+func (r *Codecs) Negotiate(accept string) (Codec, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return r.byType[r.order[0]], true
+	}
+
+	var best Codec
+	bestQ := 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q <= bestQ {
+			continue
+		}
+
+		var c Codec
+		switch mediaType {
+		case "*/*", "application/*":
+			c = r.byType[r.order[0]]
+		default:
+			c = r.byType[mediaType]
+		}
+		if c != nil {
+			best, bestQ = c, q
+		}
+	}
+	return best, best != nil
+}
+
+// Binder decodes request bodies with the codec matching Content-Type, and
+// leaves path and query params to Echo's default binder.
+type Binder struct {
+	echo.DefaultBinder
+	codecs *Codecs
+}
+
+func (b *Binder) Bind(i any, c echo.Context) error {
+	if err := b.BindPathParams(c, i); err != nil {
+		return err
+	}
+	req := c.Request()
+	if req.ContentLength == 0 {
+		return nil
+	}
+
+	codec, ok := b.codecs.ForContentType(req.Header.Get(echo.HeaderContentType))
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnsupportedMediaType, "Content-Type must be one of "+strings.Join(b.codecs.order, ", "))
+	}
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	if err := codec.Unmarshal(data, i); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid "+codec.MediaType()+" body").SetInternal(err)
+	}
+	return nil
+}
+
+// render writes v in the format the client asked for.
+func render(c echo.Context, codecs *Codecs, status int, v any) error {
+	codec, ok := codecs.Negotiate(c.Request().Header.Get(echo.HeaderAccept))
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotAcceptable, "Accept must allow one of "+strings.Join(codecs.order, ", "))
+	}
+	data, err := codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+	c.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+	return c.Blob(status, codec.MediaType(), data)
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) MediaType() string                  { return echo.MIMEApplicationJSON }
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) MediaType() string                  { return MIMEMsgpack }
+func (msgpackCodec) Marshal(v any) ([]byte, error)      { return msgpack.Marshal(v) }
+func (msgpackCodec) Unmarshal(data []byte, v any) error { return msgpack.Unmarshal(data, v) }
+
+// protobufCodec needs a schema, so it only handles types that convert to
+// and from a generated message.
+type protobufCodec struct{}
+
+func (protobufCodec) MediaType() string { return MIMEProtobuf }
+
+func (protobufCodec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(protoMessage)
+	if !ok {
+		return nil, fmt.Errorf("protobuf: %T has no message type", v)
+	}
+	return proto.Marshal(m.ToProto())
+}
+
+func (protobufCodec) Unmarshal(data []byte, v any) error {
+	t, ok := v.(protoTarget)
+	if !ok {
+		return errors.New("protobuf: cannot decode into " + fmt.Sprintf("%T", v))
+	}
+	return t.FromProto(data)
+}
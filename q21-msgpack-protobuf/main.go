@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// MessagePack and Protobuf: one API answering in JSON, MessagePack or
+// Protobuf depending on Accept, and reading request bodies in any of them
+// depending on Content-Type. Binary formats cut payload size and parse
+// time for mobile and IoT clients on slow or metered networks.
+func main() {
+	e := echo.New()
+	e.Use(middleware.Logger())
+
+	codecs := NewCodecs(jsonCodec{}, msgpackCodec{}, protobufCodec{})
+	e.Binder = &Binder{codecs: codecs}
+
+	// errors go out in the negotiated format too, so a protobuf client
+	// never has to parse JSON
+	e.HTTPErrorHandler = func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+		code, msg := http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError)
+		var he *echo.HTTPError
+		if errors.As(err, &he) {
+			code, msg = he.Code, fmt.Sprint(he.Message)
+		} else {
+			c.Logger().Error(err)
+		}
+		if err := render(c, codecs, code, &ErrorBody{Message: msg}); err != nil {
+			// nothing acceptable to the client: fall back to plain text
+			_ = c.String(code, msg)
+		}
+	}
+
+	var (
+		mu       sync.RWMutex
+		products = map[int64]Product{}
+		nextID   = int64(1)
+	)
+	for _, p := range []Product{
+		{Name: "Sensor", PriceCents: 1299, Tags: []string{"iot", "temperature"}, InStock: true},
+		{Name: "Gateway", PriceCents: 8900, Tags: []string{"iot", "lora"}, InStock: false},
+		{Name: "Battery pack", PriceCents: 2450, Tags: []string{"power"}, InStock: true},
+	} {
+		p.ID = nextID
+		products[p.ID] = p
+		nextID++
+	}
+
+	e.GET("/products", func(c echo.Context) error {
+		mu.RLock()
+		defer mu.RUnlock()
+		list := &ProductList{Products: make([]Product, 0, len(products))}
+		for id := int64(1); id < nextID; id++ {
+			if p, ok := products[id]; ok {
+				list.Products = append(list.Products, p)
+			}
+		}
+		return render(c, codecs, http.StatusOK, list)
+	})
+
+	e.GET("/products/:id", func(c echo.Context) error {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "id must be an integer")
+		}
+		mu.RLock()
+		p, ok := products[id]
+		mu.RUnlock()
+		if !ok {
+			return echo.NewHTTPError(http.StatusNotFound, "product not found")
+		}
+		return render(c, codecs, http.StatusOK, &p)
+	})
+
+	e.POST("/products", func(c echo.Context) error {
+		var p Product
+		if err := c.Bind(&p); err != nil {
+			return err
+		}
+		if p.Name == "" {
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, "name is required")
+		}
+		mu.Lock()
+		p.ID = nextID
+		products[p.ID] = p
+		nextID++
+		mu.Unlock()
+		return render(c, codecs, http.StatusCreated, &p)
+	})
+
+	srv, err := config.LoadServer(config.DefaultServer("3000"))
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	srv.Apply(e.Server)
+
+	if err := e.Start(srv.Addr()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		e.Logger.Fatal(err)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"github.com/jabeedhexanovamedia/msgpack-protobuf/catalogpb"
+	"google.golang.org/protobuf/proto"
+)
+
+// Product is the type handlers work with. It carries json and msgpack tags
+// for those codecs, and converts to and from catalogpb for protobuf, so
+// the generated types stay at the edge.
+type Product struct {
+	ID         int64    `json:"id" msgpack:"id"`
+	Name       string   `json:"name" msgpack:"name"`
+	PriceCents int64    `json:"price_cents" msgpack:"price_cents"`
+	Tags       []string `json:"tags" msgpack:"tags"`
+	InStock    bool     `json:"in_stock" msgpack:"in_stock"`
+}
+
+type ProductList struct {
+	Products []Product `json:"products" msgpack:"products"`
+}
+
+// ErrorBody is every error response, in whichever format was negotiated.
+type ErrorBody struct {
+	Message string `json:"message" msgpack:"message"`
+}
+
+// protoMessage is implemented by types the protobuf codec can encode.
+type protoMessage interface {
+	ToProto() proto.Message
+}
+
+// protoTarget is implemented by types the protobuf codec can decode into.
+type protoTarget interface {
+	FromProto(data []byte) error
+}
+
+func (p *Product) ToProto() proto.Message {
+	return toPB(p)
+}
+
+func (p *Product) FromProto(data []byte) error {
+	var m catalogpb.Product
+	if err := proto.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	*p = Product{ID: m.Id, Name: m.Name, PriceCents: m.PriceCents, Tags: m.Tags, InStock: m.InStock}
+	return nil
+}
+
+func (l *ProductList) ToProto() proto.Message {
+	m := &catalogpb.ProductList{Products: make([]*catalogpb.Product, len(l.Products))}
+	for i := range l.Products {
+		m.Products[i] = toPB(&l.Products[i])
+	}
+	return m
+}
+
+func (e *ErrorBody) ToProto() proto.Message {
+	return &catalogpb.Error{Message: e.Message}
+}
+
+func toPB(p *Product) *catalogpb.Product {
+	return &catalogpb.Product{Id: p.ID, Name: p.Name, PriceCents: p.PriceCents, Tags: p.Tags, InStock: p.InStock}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	_ "modernc.org/sqlite"
+)
+
+// Transaction Middleware: every /api request runs in one SQLite
+// transaction, opened by middleware, reached by handlers through Tx(c),
+// committed on success and rolled back on an error or a panic.
+//
+//	DB_PATH  SQLite file (default bank.db)
+func main() {
+	e := echo.New()
+	e.Use(middleware.Logger())
+	e.Use(middleware.Recover())
+
+	path, _ := config.GetEnv("DB_PATH", "bank.db")
+	// busy_timeout makes a second writer wait for the first instead of
+	// failing with SQLITE_BUSY; _txlock=immediate takes the write lock at
+	// BEGIN, so two transfers cannot both read a balance and then collide
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)&_txlock=immediate")
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	defer db.Close()
+	if err := migrate(context.Background(), db); err != nil {
+		e.Logger.Fatal(err)
+	}
+
+	api := e.Group("/api", Transaction(db))
+
+	api.GET("/accounts", func(c echo.Context) error {
+		accounts, err := listAccounts(c.Request().Context(), Tx(c))
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, accounts)
+	})
+
+	// The debit runs before the credit. When the credit fails (unknown
+	// account) or the handler panics, the rollback undoes the debit too.
+	api.POST("/transfers", func(c echo.Context) error {
+		var t Transfer
+		if err := c.Bind(&t); err != nil {
+			return err
+		}
+		if t.Amount <= 0 || t.From == t.To {
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, "amount must be positive and accounts must differ")
+		}
+
+		ctx, tx := c.Request().Context(), Tx(c)
+		if err := adjustBalance(ctx, tx, t.From, -t.Amount); err != nil {
+			return transferError(err)
+		}
+		if c.QueryParam("fail") == "panic" {
+			panic("simulated crash between debit and credit")
+		}
+		if err := adjustBalance(ctx, tx, t.To, t.Amount); err != nil {
+			return transferError(err)
+		}
+
+		t, err := insertTransfer(ctx, tx, t)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusCreated, t)
+	})
+
+	srv, err := config.LoadServer(config.DefaultServer("3000"))
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	srv.Apply(e.Server)
+
+	if err := e.Start(srv.Addr()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		e.Logger.Fatal(err)
+	}
+}
+
+func transferError(err error) error {
+	switch {
+	case errors.Is(err, errAccountNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, errInsufficientFunds):
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	}
+	return err
+}
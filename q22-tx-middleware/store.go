@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var (
+	errAccountNotFound   = errors.New("account not found")
+	errInsufficientFunds = errors.New("insufficient funds")
+)
+
+// dbtx is what both *sql.DB and *sql.Tx offer, so the queries below run
+// inside the request's transaction or, in setup code, directly on the DB.
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type Account struct {
+	ID      int64  `json:"id"`
+	Owner   string `json:"owner"`
+	Balance int64  `json:"balance"`
+}
+
+type Transfer struct {
+	ID        int64     `json:"id"`
+	From      int64     `json:"from"`
+	To        int64     `json:"to"`
+	Amount    int64     `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+const schema = `
+CREATE TABLE IF NOT EXISTS accounts (
+	id      INTEGER PRIMARY KEY,
+	owner   TEXT NOT NULL,
+	balance INTEGER NOT NULL CHECK (balance >= 0)
+);
+CREATE TABLE IF NOT EXISTS transfers (
+	id         INTEGER PRIMARY KEY,
+	from_id    INTEGER NOT NULL REFERENCES accounts(id),
+	to_id      INTEGER NOT NULL REFERENCES accounts(id),
+	amount     INTEGER NOT NULL CHECK (amount > 0),
+	created_at TEXT NOT NULL
+);`
+
+// migrate creates the tables and, on an empty database, two accounts.
+func migrate(ctx context.Context, db dbtx) error {
+	if _, err := db.ExecContext(ctx, schema); err != nil {
+		return err
+	}
+	_, err := db.ExecContext(ctx, `INSERT OR IGNORE INTO accounts (id, owner, balance) VALUES (1, 'alice', 10000), (2, 'bob', 5000)`)
+	return err
+}
+
+func listAccounts(ctx context.Context, db dbtx) ([]Account, error) {
+	rows, err := db.QueryContext(ctx, `SELECT id, owner, balance FROM accounts ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Account
+	for rows.Next() {
+		var a Account
+		if err := rows.Scan(&a.ID, &a.Owner, &a.Balance); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// adjustBalance adds delta to an account; a negative delta that would take
+// the balance below zero fails with errInsufficientFunds.
+func adjustBalance(ctx context.Context, db dbtx, id, delta int64) error {
+	res, err := db.ExecContext(ctx,
+		`UPDATE accounts SET balance = balance + ? WHERE id = ? AND balance + ? >= 0`, delta, id, delta)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 1 {
+		return nil
+	}
+	var exists bool
+	if err := db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM accounts WHERE id = ?)`, id).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return errAccountNotFound
+	}
+	return errInsufficientFunds
+}
+
+func insertTransfer(ctx context.Context, db dbtx, t Transfer) (Transfer, error) {
+	t.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	res, err := db.ExecContext(ctx,
+		`INSERT INTO transfers (from_id, to_id, amount, created_at) VALUES (?, ?, ?, ?)`,
+		t.From, t.To, t.Amount, t.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		return t, err
+	}
+	t.ID, err = res.LastInsertId()
+	return t, err
+}
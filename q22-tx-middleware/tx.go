@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+const txContextKey = "tx"
+
+// Transaction runs each request in a database transaction. It commits
+// when the handler returns nil with a status below 400, and rolls back on
+// an error, an error status or a panic.
+//
+// The response is held back until the commit has succeeded. Otherwise a
+// client could see "201 Created" for a row that a failed commit threw
+// away. The cost is that handlers cannot stream.
+func Transaction(db *sql.DB) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			req := c.Request()
+			tx, err := db.BeginTx(req.Context(), &sql.TxOptions{
+				ReadOnly: req.Method == http.MethodGet || req.Method == http.MethodHead,
+			})
+			if err != nil {
+				return err
+			}
+			c.Set(txContextKey, tx)
+
+			res := c.Response()
+			orig := res.Writer
+			buf := &bufferedWriter{header: http.Header{}}
+			res.Writer = buf
+
+			// a panic must not leave the transaction open: roll back, put
+			// the real writer back and let Recover deal with the panic
+			defer func() {
+				if r := recover(); r != nil {
+					_ = tx.Rollback()
+					resetResponse(res, orig)
+					panic(r)
+				}
+			}()
+
+			err = next(c)
+			if err == nil && res.Status < http.StatusBadRequest {
+				err = tx.Commit()
+			} else {
+				_ = tx.Rollback()
+			}
+
+			if err != nil {
+				// drop whatever the handler wrote; the error handler
+				// writes the real answer to the client
+				resetResponse(res, orig)
+				return err
+			}
+			res.Writer = orig
+			return buf.flushTo(orig)
+		}
+	}
+}
+
+// Tx returns the request's transaction. It panics when Transaction is not
+// in the chain, which is a wiring bug, not something to handle at runtime.
+func Tx(c echo.Context) *sql.Tx {
+	tx, ok := c.Get(txContextKey).(*sql.Tx)
+	if !ok {
+		panic("Tx: no transaction in context; is the Transaction middleware registered for " + c.Path() + "?")
+	}
+	return tx
+}
+
+// resetResponse points res back at w as if nothing had been written.
+func resetResponse(res *echo.Response, w http.ResponseWriter) {
+	res.Writer = w
+	res.Status = 0
+	res.Size = 0
+	res.Committed = false
+}
+
+// bufferedWriter collects a response in memory until the transaction has
+// committed.
+type bufferedWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) Header() http.Header         { return w.header }
+func (w *bufferedWriter) WriteHeader(status int)      { w.status = status }
+func (w *bufferedWriter) Write(b []byte) (int, error) { return w.body.Write(b) }
+
+func (w *bufferedWriter) flushTo(dst http.ResponseWriter) error {
+	for k, v := range w.header {
+		dst.Header()[k] = v
+	}
+	if w.status != 0 {
+		dst.WriteHeader(w.status)
+	}
+	_, err := w.body.WriteTo(dst)
+	return err
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+)
+
+// maxEntry is the largest body worth caching; bigger responses pass
+// through uncached rather than filling Redis with them.
+const maxEntry = 1 << 20
+
+// Cache stores GET responses in Redis. Every entry carries tags, and a
+// write invalidates the tags it touches, which drops every cached
+// response that could show the old data.
+//
+// Keys look like:
+//
+//	cache:page:/products?category=tools  cached response (string, TTL)
+//	cache:tag:products                   keys tagged "products" (set)
+type Cache struct {
+	rdb    *redis.Client
+	prefix string
+	ttl    time.Duration
+}
+
+func NewCache(rdb *redis.Client, prefix string, ttl time.Duration) *Cache {
+	return &Cache{rdb: rdb, prefix: prefix, ttl: ttl}
+}
+
+// TagFunc names the tags of the current request, e.g. "product:42".
+type TagFunc func(c echo.Context) []string
+
+// Tags builds a TagFunc from fixed names, where {param} is replaced with
+// the path parameter: Tags("products", "product:{id}").
+func Tags(patterns ...string) TagFunc {
+	return func(c echo.Context) []string {
+		tags := make([]string, len(patterns))
+		for i, p := range patterns {
+			for _, name := range c.ParamNames() {
+				p = strings.ReplaceAll(p, "{"+name+"}", c.Param(name))
+			}
+			tags[i] = p
+		}
+		return tags
+	}
+}
+
+// entry is what is stored per cached response. Only 200s are cached, so
+// the status is implied.
+type entry struct {
+	ContentType string    `json:"content_type"`
+	Body        []byte    `json:"body"`
+	StoredAt    time.Time `json:"stored_at"`
+}
+
+// Middleware serves GET requests from the cache and stores 200 responses
+// under tags. It sets X-Cache to HIT, MISS or BYPASS.
+//
+// A request with Cache-Control: no-cache skips the lookup but refreshes
+// the entry; no-store neither reads nor writes. Requests carrying
+// Authorization are never cached, since the key does not include the
+// user. When Redis is unreachable the request is served uncached.
+func (ca *Cache) Middleware(tags TagFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if req.Method != http.MethodGet || req.Header.Get(echo.HeaderAuthorization) != "" {
+				return next(c)
+			}
+
+			cc := req.Header.Get("Cache-Control")
+			if strings.Contains(cc, "no-store") {
+				c.Response().Header().Set("X-Cache", "BYPASS")
+				return next(c)
+			}
+
+			ctx := req.Context()
+			key := ca.pageKey(c)
+			if !strings.Contains(cc, "no-cache") {
+				if e, ok := ca.get(ctx, c, key); ok {
+					h := c.Response().Header()
+					h.Set("X-Cache", "HIT")
+					h.Set("Age", strconv.Itoa(int(time.Since(e.StoredAt).Seconds())))
+					return c.Blob(http.StatusOK, e.ContentType, e.Body)
+				}
+				c.Response().Header().Set("X-Cache", "MISS")
+			} else {
+				c.Response().Header().Set("X-Cache", "BYPASS")
+			}
+
+			res := c.Response()
+			rec := &recorder{ResponseWriter: res.Writer}
+			res.Writer = rec
+			err := next(c)
+			res.Writer = rec.ResponseWriter
+
+			if err != nil || res.Status != http.StatusOK || rec.overflow {
+				return err
+			}
+			e := entry{
+				ContentType: res.Header().Get(echo.HeaderContentType),
+				Body:        rec.body.Bytes(),
+				StoredAt:    time.Now(),
+			}
+			if err := ca.set(ctx, key, e, tags(c)); err != nil {
+				c.Logger().Warnf("cache: store %s: %v", key, err)
+			}
+			return nil
+		}
+	}
+}
+
+// Invalidates drops the tags named by tags once a write has succeeded.
+// Failed writes changed nothing, so the cache is left alone.
+func (ca *Cache) Invalidates(tags TagFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if err := next(c); err != nil {
+				return err
+			}
+			if c.Response().Status >= http.StatusBadRequest {
+				return nil
+			}
+			if err := ca.Invalidate(c.Request().Context(), tags(c)...); err != nil {
+				c.Logger().Warnf("cache: invalidate %v: %v", tags(c), err)
+			}
+			return nil
+		}
+	}
+}
+
+// Invalidate deletes every entry carrying one of tags, and the tag sets
+// themselves.
+func (ca *Cache) Invalidate(ctx context.Context, tags ...string) error {
+	for _, tag := range tags {
+		tagKey := ca.prefix + "tag:" + tag
+		keys, err := ca.rdb.SMembers(ctx, tagKey).Result()
+		if err != nil {
+			return err
+		}
+		if err := ca.rdb.Del(ctx, append(keys, tagKey)...).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pageKey is the path plus the query in canonical order, so ?a=1&b=2 and
+// ?b=2&a=1 share one entry.
+func (ca *Cache) pageKey(c echo.Context) string {
+	u := c.Request().URL
+	key := ca.prefix + "page:" + u.Path
+	if q := u.Query().Encode(); q != "" {
+		key += "?" + q
+	}
+	return key
+}
+
+func (ca *Cache) get(ctx context.Context, c echo.Context, key string) (entry, bool) {
+	var e entry
+	raw, err := ca.rdb.Get(ctx, key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			c.Logger().Warnf("cache: get %s: %v", key, err)
+		}
+		return e, false
+	}
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return e, false
+	}
+	return e, true
+}
+
+// set writes the entry and adds its key to each tag set in one
+// MULTI/EXEC. A tag set's TTL is pushed out with every new member, so it
+// never expires before the entries it points at.
+func (ca *Cache) set(ctx context.Context, key string, e entry, tags []string) error {
+	raw, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = ca.rdb.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.Set(ctx, key, raw, ca.ttl)
+		for _, tag := range tags {
+			tagKey := ca.prefix + "tag:" + tag
+			p.SAdd(ctx, tagKey, key)
+			p.Expire(ctx, tagKey, ca.ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+// recorder passes the response through to the client and keeps a copy of
+// the body for the cache.
+type recorder struct {
+	http.ResponseWriter
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if !r.overflow {
+		if r.body.Len()+len(b) > maxEntry {
+			r.overflow = true
+			r.body.Reset()
+		} else {
+			r.body.Write(b)
+		}
+	}
+	return r.ResponseWriter.Write(b)
+}
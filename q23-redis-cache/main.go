@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/labstack/gommon/log"
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis Response Cache: GET responses are cached in Redis by path and
+// query for a TTL, and each write drops the cached pages it made stale.
+//
+//	REDIS_URL    redis://localhost:6379/0; unset starts an in-process Redis
+//	CACHE_TTL    how long a response stays cached (default 60s)
+//	STORE_DELAY  simulated query time of the catalog (default 300ms)
+func main() {
+	e := echo.New()
+	e.Use(middleware.Logger())
+	e.Logger.SetLevel(log.WARN)
+
+	ttl, err := config.GetEnvDuration("CACHE_TTL", time.Minute)
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	delay, err := config.GetEnvDuration("STORE_DELAY", 300*time.Millisecond)
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+
+	rdb, err := redisClient(e)
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	defer rdb.Close()
+
+	store := NewStore(delay)
+	cache := NewCache(rdb, "cache:", ttl)
+
+	// The list shows every product, so it carries the "products" tag that
+	// every write drops. A single product only goes stale when that product
+	// changes.
+	list := Tags("products")
+	item := Tags("products", "product:{id}")
+
+	e.GET("/products", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, store.List(c.QueryParam("category")))
+	}, cache.Middleware(list))
+
+	e.GET("/products/:id", func(c echo.Context) error {
+		id, err := idParam(c)
+		if err != nil {
+			return err
+		}
+		p, err := store.Get(id)
+		if err != nil {
+			return productError(err)
+		}
+		return c.JSON(http.StatusOK, p)
+	}, cache.Middleware(Tags("product:{id}")))
+
+	e.POST("/products", func(c echo.Context) error {
+		var p Product
+		if err := c.Bind(&p); err != nil {
+			return err
+		}
+		return c.JSON(http.StatusCreated, store.Create(p))
+	}, cache.Invalidates(list))
+
+	e.PUT("/products/:id", func(c echo.Context) error {
+		id, err := idParam(c)
+		if err != nil {
+			return err
+		}
+		var p Product
+		if err := c.Bind(&p); err != nil {
+			return err
+		}
+		p.ID = id
+		if err := store.Update(p); err != nil {
+			return productError(err)
+		}
+		return c.JSON(http.StatusOK, p)
+	}, cache.Invalidates(item))
+
+	e.DELETE("/products/:id", func(c echo.Context) error {
+		id, err := idParam(c)
+		if err != nil {
+			return err
+		}
+		if err := store.Delete(id); err != nil {
+			return productError(err)
+		}
+		return c.NoContent(http.StatusNoContent)
+	}, cache.Invalidates(item))
+
+	srv, err := config.LoadServer(config.DefaultServer("3000"))
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	srv.Apply(e.Server)
+
+	if err := e.Start(srv.Addr()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		e.Logger.Fatal(err)
+	}
+}
+
+// redisClient connects to REDIS_URL, or starts an in-memory Redis so the
+// example runs without one installed.
+func redisClient(e *echo.Echo) (*redis.Client, error) {
+	url, _ := config.GetEnv("REDIS_URL", "")
+	if url == "" {
+		mr, err := miniredis.Run()
+		if err != nil {
+			return nil, err
+		}
+		e.Logger.Warnf("REDIS_URL not set, using in-process Redis at %s", mr.Addr())
+		// miniredis only ages keys when told to; tick it along with the clock
+		go func() {
+			for range time.Tick(time.Second) {
+				mr.FastForward(time.Second)
+			}
+		}()
+		url = "redis://" + mr.Addr()
+	}
+
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	rdb := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		return nil, err
+	}
+	return rdb, nil
+}
+
+func idParam(c echo.Context) (int, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "id must be a number")
+	}
+	return id, nil
+}
+
+func productError(err error) error {
+	if errors.Is(err, errNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	return err
+}
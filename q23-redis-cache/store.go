@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var errNotFound = errors.New("product not found")
+
+type Product struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Price    int    `json:"price"` // cents
+}
+
+// Store is an in-memory catalog that answers slowly on purpose, standing
+// in for the database query a cache is there to avoid.
+type Store struct {
+	mu       sync.RWMutex
+	products map[int]Product
+	nextID   int
+	delay    time.Duration
+}
+
+func NewStore(delay time.Duration) *Store {
+	s := &Store{products: map[int]Product{}, delay: delay}
+	for _, p := range []Product{
+		{Name: "Hammer", Category: "tools", Price: 1299},
+		{Name: "Screwdriver", Category: "tools", Price: 599},
+		{Name: "Desk lamp", Category: "home", Price: 2499},
+	} {
+		s.Create(p)
+	}
+	return s
+}
+
+func (s *Store) List(category string) []Product {
+	time.Sleep(s.delay)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := []Product{}
+	for _, p := range s.products {
+		if category == "" || strings.EqualFold(p.Category, category) {
+			out = append(out, p)
+		}
+	}
+	slices.SortFunc(out, func(a, b Product) int { return a.ID - b.ID })
+	return out
+}
+
+func (s *Store) Get(id int) (Product, error) {
+	time.Sleep(s.delay)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	p, ok := s.products[id]
+	if !ok {
+		return Product{}, errNotFound
+	}
+	return p, nil
+}
+
+func (s *Store) Create(p Product) Product {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	p.ID = s.nextID
+	s.products[p.ID] = p
+	return p
+}
+
+func (s *Store) Update(p Product) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.products[p.ID]; !ok {
+		return errNotFound
+	}
+	s.products[p.ID] = p
+	return nil
+}
+
+func (s *Store) Delete(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.products[id]; !ok {
+		return errNotFound
+	}
+	delete(s.products, id)
+	return nil
+}
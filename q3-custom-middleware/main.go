@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"os"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	sharedmw "github.com/jabeedhexanovamedia/go-echo-practice/pkg/middleware"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+// Custom Middleware: hand-written timing, header injection and
+// short-circuiting middleware, and where each one runs depending on whether
+// it is registered with Pre, Use, a group or a single route. Every /order
+// endpoint answers with the trace of middleware that ran before it.
+func main() {
+	e := echo.New()
+	e.HideBanner = true
+	e.Logger.SetLevel(log.INFO)
+
+	apiKey := os.Getenv("API_KEY")
+	if apiKey == "" {
+		apiKey = "secret"
+	}
+	routes(e, apiKey, os.Getenv("MAINTENANCE") == "true")
+
+	srv, err := config.LoadServer(config.DefaultServer("3000"))
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	srv.Apply(e.Server)
+
+	if err := e.Start(srv.Addr()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		e.Logger.Fatal(err)
+	}
+}
+
+// routes registers the middleware and routes on e. It takes e rather than
+// creating it, so main_test.go can put a recorder in front of everything.
+func routes(e *echo.Echo, apiKey string, maintenanceOn bool) {
+	// Pre runs before the router, so it sees every request, even 404s,
+	// and could still rewrite the path
+	e.Pre(step("pre"))
+
+	// Use runs after routing, for every matched route, in registration order
+	e.Use(timing)
+	e.Use(headers(map[string]string{"X-Powered-By": "q3-custom-middleware"}))
+	e.Use(maintenance(maintenanceOn))
+	e.Use(step("root"))
+
+	e.GET("/health", func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+	e.GET("/order", order)
+
+	// group middleware runs after e.Use and only for routes in the group
+	api := e.Group("/api", step("api"))
+	api.GET("/order", order)
+
+	// route middleware runs last, closest to the handler, left to right
+	api.GET("/route", order, step("route-1"), step("route-2"))
+
+	// nested group: parent group middleware first, then the child's
+	v1 := api.Group("/v1", step("v1"))
+	v1.GET("/order", order)
+
+	// short-circuit: without the key the handler (and step "admin") never
+	// runs. The key check is the shared net/http one, adapted by
+	// WrapMiddleware; it answers 401 without calling next.
+	requireKey := echo.WrapMiddleware(sharedmw.APIKey("X-API-Key", sharedmw.Keys(apiKey)))
+	admin := e.Group("/admin", requireKey, step("admin"))
+	admin.GET("/order", order)
+}
+
+// order returns the middleware that ran before this handler, in order.
+func order(c echo.Context) error {
+	return c.JSON(http.StatusOK, echo.Map{
+		"path":  c.Path(),
+		"trace": getTrace(c),
+	})
+}
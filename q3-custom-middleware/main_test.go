@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+const testKey = "test-key"
+
+// newTestServer builds the app behind a Pre middleware registered before
+// everything else. Being outermost, it sees the finished trace after
+// next(c) returns, for short-circuited requests too.
+func newTestServer(maintenanceOn bool, got *[]string) *echo.Echo {
+	e := echo.New()
+	e.Logger.SetOutput(io.Discard)
+	e.Pre(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			err := next(c)
+			*got = getTrace(c)
+			return err
+		}
+	})
+	routes(e, testKey, maintenanceOn)
+	return e
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		key         string
+		maintenance bool
+		wantStatus  int
+		wantTrace   []string
+	}{
+		{
+			name: "global", target: "/order", wantStatus: http.StatusOK,
+			wantTrace: []string{"→pre", "→root", "←root", "←pre"},
+		},
+		{
+			name: "group", target: "/api/order", wantStatus: http.StatusOK,
+			wantTrace: []string{"→pre", "→root", "→api", "←api", "←root", "←pre"},
+		},
+		{
+			name: "route, left to right", target: "/api/route", wantStatus: http.StatusOK,
+			wantTrace: []string{"→pre", "→root", "→api", "→route-1", "→route-2", "←route-2", "←route-1", "←api", "←root", "←pre"},
+		},
+		{
+			name: "nested group", target: "/api/v1/order", wantStatus: http.StatusOK,
+			wantTrace: []string{"→pre", "→root", "→api", "→v1", "←v1", "←api", "←root", "←pre"},
+		},
+		{
+			name: "group behind a key", target: "/admin/order", key: testKey, wantStatus: http.StatusOK,
+			wantTrace: []string{"→pre", "→root", "→admin", "←admin", "←root", "←pre"},
+		},
+		{
+			// requireKey returns before step("admin") and the handler
+			name: "short-circuit without key", target: "/admin/order", wantStatus: http.StatusUnauthorized,
+			wantTrace: []string{"→pre", "→root", "←root", "←pre"},
+		},
+		{
+			name: "short-circuit with wrong key", target: "/admin/order", key: "nope", wantStatus: http.StatusUnauthorized,
+			wantTrace: []string{"→pre", "→root", "←root", "←pre"},
+		},
+		{
+			// maintenance is registered before step("root"), so only Pre ran
+			name: "maintenance short-circuit", target: "/api/order", maintenance: true, wantStatus: http.StatusServiceUnavailable,
+			wantTrace: []string{"→pre", "←pre"},
+		},
+		{
+			name: "health during maintenance", target: "/health", maintenance: true, wantStatus: http.StatusOK,
+			wantTrace: []string{"→pre", "→root", "←root", "←pre"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var trace []string
+			e := newTestServer(tt.maintenance, &trace)
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.key != "" {
+				req.Header.Set("X-API-Key", tt.key)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("GET %s = %d, want %d: %s", tt.target, rec.Code, tt.wantStatus, rec.Body)
+			}
+			if !slices.Equal(trace, tt.wantTrace) {
+				t.Errorf("trace = %v, want %v", trace, tt.wantTrace)
+			}
+			// headers() runs before maintenance, so it is set either way
+			if got := rec.Header().Get("X-Powered-By"); got != "q3-custom-middleware" {
+				t.Errorf("X-Powered-By = %q", got)
+			}
+		})
+	}
+}
+
+// TestHandlerSeesInboundHalf checks the body the handler writes: at that
+// point only the "→" half of the trace exists.
+func TestHandlerSeesInboundHalf(t *testing.T) {
+	var trace []string
+	e := newTestServer(false, &trace)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/route", nil))
+
+	var body struct {
+		Path  string   `json:"path"`
+		Trace []string `json:"trace"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"→pre", "→root", "→api", "→route-1", "→route-2"}
+	if body.Path != "/api/route" || !slices.Equal(body.Trace, want) {
+		t.Errorf("body = %+v, want path /api/route and trace %v", body, want)
+	}
+	if rec.Header().Get("X-Response-Time") == "" {
+		t.Error("X-Response-Time not set")
+	}
+}
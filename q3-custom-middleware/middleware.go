@@ -0,0 +1,88 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// An Echo middleware is a function that takes the next handler and returns
+// a new handler wrapping it:
+//
+//	func(next echo.HandlerFunc) echo.HandlerFunc
+//
+// Code before next(c) runs on the way in, code after it on the way out.
+// Returning without calling next short-circuits the rest of the chain.
+
+const traceKey = "trace"
+
+// step records "→name" on the way in and "←name" on the way out, so the
+// trace of a request shows exactly which middleware ran and in what order.
+func step(name string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			addTrace(c, "→"+name)
+			err := next(c)
+			addTrace(c, "←"+name)
+			return err
+		}
+	}
+}
+
+func addTrace(c echo.Context, entry string) {
+	trace, _ := c.Get(traceKey).([]string)
+	c.Set(traceKey, append(trace, entry))
+}
+
+func getTrace(c echo.Context) []string {
+	trace, _ := c.Get(traceKey).([]string)
+	return trace
+}
+
+// timing measures the whole chain below it. The header has to be set in
+// Response().Before: once the handler has written the body, headers are
+// already on the wire and setting them after next(c) does nothing.
+func timing(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
+		c.Response().Before(func() {
+			c.Response().Header().Set("X-Response-Time", time.Since(start).String())
+		})
+
+		err := next(c)
+
+		// the full trace, including the "←" half, only exists at this point
+		c.Logger().Infof("%s %s took %s trace=%s", c.Request().Method, c.Request().URL.Path,
+			time.Since(start), strings.Join(getTrace(c), " "))
+		return err
+	}
+}
+
+// headers injects fixed response headers. Setting them before next(c) means
+// they are present even when a later middleware or the handler errors.
+func headers(values map[string]string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			for k, v := range values {
+				c.Response().Header().Set(k, v)
+			}
+			return next(c)
+		}
+	}
+}
+
+// maintenance short-circuits every request with 503 while enabled; nothing
+// registered after it, including the handler, runs.
+func maintenance(enabled bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !enabled || c.Request().URL.Path == "/health" {
+				return next(c)
+			}
+			c.Response().Header().Set("Retry-After", "120")
+			return echo.NewHTTPError(http.StatusServiceUnavailable, "down for maintenance")
+		}
+	}
+}
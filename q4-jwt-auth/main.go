@@ -0,0 +1,147 @@
+package main
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"os"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	echojwt "github.com/labstack/echo-jwt/v4"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+type User struct {
+	Username string
+	Password string
+	Role     string
+}
+
+// users is a stand-in for a user table. A real one stores bcrypt hashes
+// (see q2-json-response), never plain passwords.
+var users = map[string]User{
+	"alice": {Username: "alice", Password: "alice-pass", Role: "admin"},
+	"bob":   {Username: "bob", Password: "bob-pass", Role: "user"},
+}
+
+const claimsKey = "claims"
+
+// JWT Auth: POST /login issues an access and a refresh token, the /api group
+// only accepts a valid access token, its claims are available to handlers
+// through claimsFrom, and POST /refresh trades a refresh token for a new pair.
+func main() {
+	e := echo.New()
+	e.Use(middleware.Logger())
+
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		secret = "dev-only-secret-change-me"
+		e.Logger.Warn("JWT_SECRET not set, using an insecure development secret")
+	}
+	issuer := NewIssuer(secret)
+
+	e.POST("/login", func(c echo.Context) error {
+		var req struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+		}
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request payload")
+		}
+
+		user, ok := users[req.Username]
+		// compare even for unknown users so timing does not reveal which exist
+		match := subtle.ConstantTimeCompare([]byte(req.Password), []byte(user.Password)) == 1
+		if !ok || !match {
+			return echo.NewHTTPError(http.StatusUnauthorized, "invalid username or password")
+		}
+
+		pair, err := issuer.Pair(user)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, pair)
+	})
+
+	e.POST("/refresh", func(c echo.Context) error {
+		var req struct {
+			RefreshToken string `json:"refresh_token"`
+		}
+		if err := c.Bind(&req); err != nil || req.RefreshToken == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "refresh_token is required")
+		}
+
+		claims, err := issuer.Parse(req.RefreshToken, tokenRefresh)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired refresh token")
+		}
+
+		// re-read the user so a deleted account or changed role takes effect
+		user, ok := users[claims.Subject]
+		if !ok {
+			return echo.NewHTTPError(http.StatusUnauthorized, "user no longer exists")
+		}
+
+		pair, err := issuer.Pair(user)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, pair)
+	})
+
+	// everything under /api needs "Authorization: Bearer <access token>"
+	api := e.Group("/api", echojwt.WithConfig(echojwt.Config{
+		ContextKey: claimsKey,
+		ParseTokenFunc: func(c echo.Context, auth string) (interface{}, error) {
+			return issuer.Parse(auth, tokenAccess)
+		},
+		ErrorHandler: func(c echo.Context, err error) error {
+			c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="api"`)
+			return echo.NewHTTPError(http.StatusUnauthorized, "missing, invalid or expired access token")
+		},
+	}))
+
+	api.GET("/me", func(c echo.Context) error {
+		claims := claimsFrom(c)
+		return c.JSON(http.StatusOK, echo.Map{
+			"username":   claims.Subject,
+			"role":       claims.Role,
+			"expires_at": claims.ExpiresAt.Time,
+		})
+	})
+
+	api.GET("/admin", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, echo.Map{"message": "welcome, admin " + claimsFrom(c).Subject})
+	}, requireRole("admin"))
+
+	srv, err := config.LoadServer(config.DefaultServer("3000"))
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	srv.Apply(e.Server)
+
+	if err := e.Start(srv.Addr()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		e.Logger.Fatal(err)
+	}
+}
+
+// claimsFrom returns the claims the JWT middleware stored for this request.
+// Only call it from handlers behind that middleware.
+func claimsFrom(c echo.Context) *Claims {
+	claims, _ := c.Get(claimsKey).(*Claims)
+	return claims
+}
+
+// requireRole answers 403 for a valid token without the role. 401 means
+// "who are you?", 403 means "I know who you are, and no".
+func requireRole(role string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if claims := claimsFrom(c); claims == nil || claims.Role != role {
+				return echo.NewHTTPError(http.StatusForbidden, "requires role "+role)
+			}
+			return next(c)
+		}
+	}
+}
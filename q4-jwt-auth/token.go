@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	accessTTL  = 15 * time.Minute
+	refreshTTL = 7 * 24 * time.Hour
+
+	tokenAccess  = "access"
+	tokenRefresh = "refresh"
+)
+
+// Claims is what the server puts in every token. RegisteredClaims carries
+// the standard sub/exp/iat/iss fields; Role and Type are ours.
+type Claims struct {
+	Role string `json:"role"`
+	// Type keeps a refresh token from being accepted as an access token and
+	// the other way round; both are signed with the same key.
+	Type string `json:"typ"`
+	jwt.RegisteredClaims
+}
+
+// TokenPair is the login and refresh response.
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"` // seconds until the access token expires
+}
+
+// Issuer signs and verifies tokens with one HMAC key.
+type Issuer struct {
+	key    []byte
+	issuer string
+	now    func() time.Time
+}
+
+func NewIssuer(secret string) *Issuer {
+	return &Issuer{key: []byte(secret), issuer: "q4-jwt-auth", now: time.Now}
+}
+
+// Pair issues a fresh access and refresh token for user.
+func (i *Issuer) Pair(user User) (TokenPair, error) {
+	access, err := i.sign(user, tokenAccess, accessTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	refresh, err := i.sign(user, tokenRefresh, refreshTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	return TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(accessTTL.Seconds()),
+	}, nil
+}
+
+func (i *Issuer) sign(user User, typ string, ttl time.Duration) (string, error) {
+	now := i.now()
+	claims := Claims{
+		Role: user.Role,
+		Type: typ,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.Username,
+			Issuer:    i.issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(i.key)
+}
+
+// Parse verifies signature, expiry, issuer and token type. Only HS256 is
+// accepted: trusting the alg header would let a forged "none" or RS256
+// token through.
+func (i *Issuer) Parse(raw, typ string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(raw, claims, i.keyFunc,
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(i.issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(i.now),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Type != typ {
+		return nil, fmt.Errorf("expected %s token, got %q", typ, claims.Type)
+	}
+	return claims, nil
+}
+
+func (i *Issuer) keyFunc(t *jwt.Token) (interface{}, error) {
+	if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, errors.New("unexpected signing method")
+	}
+	return i.key, nil
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+const maxFileSize = 1 << 30 // 1 GiB per file
+
+// File Upload and Download: single and multiple multipart uploads, a
+// streaming upload that never buffers the file, and downloads with range
+// requests and a proper Content-Disposition.
+func main() {
+	e := echo.New()
+	e.Use(middleware.Logger())
+
+	storage, err := NewStorage("uploads", maxFileSize)
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+
+	// Single file, the simple way: Echo parses the form, keeping up to 32 MiB
+	// in memory and spilling the rest into a temp file.
+	e.POST("/upload", func(c echo.Context) error {
+		fh, err := c.FormFile("file")
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, `multipart field "file" is required`)
+		}
+		saved, err := saveHeader(storage, fh)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusCreated, saved)
+	})
+
+	// Several files in one field: <input type="file" name="files" multiple>
+	e.POST("/upload/multi", func(c echo.Context) error {
+		form, err := c.MultipartForm()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid multipart form")
+		}
+		defer form.RemoveAll() // temp files of large parts
+
+		if len(form.File["files"]) == 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, `multipart field "files" is required`)
+		}
+
+		saved := make([]FileInfo, 0, len(form.File["files"]))
+		for _, fh := range form.File["files"] {
+			info, err := saveHeader(storage, fh)
+			if err != nil {
+				return err
+			}
+			saved = append(saved, info)
+		}
+		return c.JSON(http.StatusCreated, saved)
+	})
+
+	// Streaming: read parts straight off the connection and io.Copy each one
+	// to disk. Nothing is buffered in memory or in temp files, so this is the
+	// one to use for large files.
+	e.POST("/upload/stream", func(c echo.Context) error {
+		mr, err := c.Request().MultipartReader()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "expected a multipart/form-data body")
+		}
+
+		var saved []FileInfo
+		for {
+			part, err := mr.NextPart()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "malformed multipart body")
+			}
+			if part.FileName() == "" {
+				continue // a plain form field
+			}
+
+			info, err := storage.Save(part.FileName(), part)
+			if err != nil {
+				return saveError(err)
+			}
+			saved = append(saved, info)
+		}
+
+		if len(saved) == 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "no files in request")
+		}
+		return c.JSON(http.StatusCreated, saved)
+	})
+
+	e.GET("/files", func(c echo.Context) error {
+		files, err := storage.List()
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, files)
+	})
+
+	// Download with Range support. ?inline=true shows the file in the
+	// browser instead of saving it, for the types in inlineTypes only.
+	e.GET("/files/:name", func(c echo.Context) error {
+		f, info, err := storage.Open(c.Param("name"))
+		if errors.Is(err, errBadName) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, "file not found")
+		}
+		defer f.Close()
+
+		disposition := "attachment"
+		if c.QueryParam("inline") == "true" && inlineSafe(info.Name()) {
+			disposition = "inline"
+		}
+
+		h := c.Response().Header()
+		// FormatMediaType quotes the name and switches to RFC 2231 encoding
+		// (filename*=utf-8''...) for non-ASCII names like "résumé.pdf".
+		h.Set(echo.HeaderContentDisposition, mime.FormatMediaType(disposition, map[string]string{"filename": info.Name()}))
+		// a strong validator lets clients resume with If-Range safely
+		h.Set("ETag", fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()))
+		h.Set("X-Content-Type-Options", "nosniff")
+		// no script, forms or same-origin access even if a file does
+		// render, e.g. a PDF viewer or a browser ignoring the disposition
+		h.Set("Content-Security-Policy", "sandbox")
+
+		// ServeContent handles Range, If-Range, If-None-Match and
+		// If-Modified-Since, answers 206/304/416, and sets Content-Type
+		// from the extension (or by sniffing).
+		http.ServeContent(c.Response(), c.Request(), info.Name(), info.ModTime(), f)
+		return nil
+	})
+
+	// big files take longer than the default 10s timeouts to move
+	defaults := config.DefaultServer("3000")
+	defaults.ReadTimeout = 10 * time.Minute
+	defaults.WriteTimeout = 10 * time.Minute
+
+	srv, err := config.LoadServer(defaults)
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	srv.Apply(e.Server)
+
+	if err := e.Start(srv.Addr()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		e.Logger.Fatal(err)
+	}
+}
+
+// inlineTypes may be shown in the browser. Anything else an upload can
+// be, HTML and SVG above all, would run its script on this origin.
+var inlineTypes = map[string]bool{
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+}
+
+// inlineSafe reports whether name's extension maps to one of inlineTypes,
+// the same lookup ServeContent uses for the Content-Type.
+func inlineSafe(name string) bool {
+	mt, _, err := mime.ParseMediaType(mime.TypeByExtension(filepath.Ext(name)))
+	return err == nil && inlineTypes[mt]
+}
+
+func saveHeader(storage *Storage, fh *multipart.FileHeader) (FileInfo, error) {
+	src, err := fh.Open()
+	if err != nil {
+		return FileInfo{}, err
+	}
+	defer src.Close()
+
+	info, err := storage.Save(fh.Filename, src)
+	if err != nil {
+		return FileInfo{}, saveError(err)
+	}
+	return info, nil
+}
+
+func saveError(err error) error {
+	switch {
+	case errors.Is(err, errBadName):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, errTooLarge):
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "file exceeds 1 GiB")
+	}
+	return err
+}
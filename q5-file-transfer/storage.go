@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	errBadName  = errors.New("invalid file name")
+	errTooLarge = errors.New("file too large")
+)
+
+// Storage keeps uploaded files flat in one directory.
+type Storage struct {
+	dir     string
+	maxSize int64
+}
+
+func NewStorage(dir string, maxSize int64) (*Storage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Storage{dir: dir, maxSize: maxSize}, nil
+}
+
+// safeName reduces a client-supplied name to a bare file name. Browsers send
+// "C:\Users\me\report.pdf" or "../../etc/passwd" just as happily as
+// "report.pdf"; only the last element is kept, and dot files are refused so
+// nothing can land outside dir or overwrite .upload temp files.
+func safeName(name string) (string, error) {
+	name = filepath.Base(strings.ReplaceAll(name, `\`, "/"))
+	if name == "." || name == "/" || name == "" || strings.HasPrefix(name, ".") {
+		return "", errBadName
+	}
+	return name, nil
+}
+
+// Save streams r to dir/name with io.Copy, so memory use is a fixed 32 KiB
+// buffer however big the file is. It writes to a temp file and renames it,
+// so a broken upload never replaces a good file with half of a new one.
+func (s *Storage) Save(name string, r io.Reader) (FileInfo, error) {
+	name, err := safeName(name)
+	if err != nil {
+		return FileInfo{}, err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return FileInfo{}, err
+	}
+	defer os.Remove(tmp.Name())
+
+	// one byte past the limit tells "exactly max" apart from "too big"
+	n, err := io.Copy(tmp, io.LimitReader(r, s.maxSize+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return FileInfo{}, err
+	}
+	if n > s.maxSize {
+		return FileInfo{}, errTooLarge
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(s.dir, name)); err != nil {
+		return FileInfo{}, err
+	}
+	return FileInfo{Name: name, Size: n}, nil
+}
+
+// Open returns the stored file and its info for serving.
+func (s *Storage) Open(name string) (*os.File, os.FileInfo, error) {
+	name, err := safeName(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f, err := os.Open(filepath.Join(s.dir, name))
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		f.Close()
+		return nil, nil, fmt.Errorf("%s: not a regular file", name)
+	}
+	return f, info, nil
+}
+
+type FileInfo struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+func (s *Storage) List() ([]FileInfo, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]FileInfo, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue // removed since ReadDir
+		}
+		files = append(files, FileInfo{Name: e.Name(), Size: info.Size()})
+	}
+	return files, nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// writeWait bounds a single write; a peer that stops reading is dropped.
+	writeWait = 10 * time.Second
+	// pongWait is how long a connection may stay silent. Every pong (or
+	// message) pushes the read deadline forward by this much.
+	pongWait = 60 * time.Second
+	// pingPeriod must be shorter than pongWait so the pong arrives in time.
+	pingPeriod = pongWait * 9 / 10
+	// maxMessageSize caps incoming messages; larger ones close the connection.
+	maxMessageSize = 4096
+	// sendBuffer is how many outgoing messages may queue per client.
+	sendBuffer = 32
+)
+
+// Message is what clients receive; they only send the text.
+type Message struct {
+	Room string    `json:"room"`
+	From string    `json:"from"`
+	Text string    `json:"text"`
+	Time time.Time `json:"time"`
+}
+
+// Client is one WebSocket connection. gorilla/websocket allows one
+// concurrent reader and one concurrent writer, so each client runs exactly
+// one readPump and one writePump goroutine and nothing else touches conn.
+type Client struct {
+	hub  *Hub
+	conn *websocket.Conn
+	send chan []byte
+	room string
+	name string
+}
+
+// readPump reads messages until the connection fails or closes, then
+// leaves the room. It runs on the request goroutine.
+func (c *Client) readPump() {
+	defer c.hub.Leave(c)
+
+	c.conn.SetReadLimit(maxMessageSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, text, err := c.conn.ReadMessage()
+		if err != nil {
+			// close frames, timeouts and resets all end up here
+			return
+		}
+		_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+
+		msg, err := json.Marshal(Message{Room: c.room, From: c.name, Text: string(text), Time: time.Now().UTC()})
+		if err != nil {
+			continue
+		}
+		c.hub.Broadcast(c.room, msg)
+	}
+}
+
+// writePump sends queued messages and pings. When the hub closes c.send it
+// sends a close frame and closes the connection, which also ends readPump.
+func (c *Client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+		c.hub.wg.Done()
+	}()
+
+	for {
+		select {
+		case msg, ok := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				// removed by the hub: leaving, too slow, or server shutdown
+				_ = c.conn.WriteMessage(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseGoingAway, "bye"))
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				return
+			}
+
+		case <-ticker.C:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
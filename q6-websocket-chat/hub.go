@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var errHubClosed = errors.New("hub is shutting down")
+
+// Hub tracks which clients are in which room. It owns every client's send
+// channel: only the hub closes it, and always while removing the client
+// under the lock, so a channel is never closed twice or sent on after close.
+type Hub struct {
+	mu     sync.Mutex
+	rooms  map[string]map[*Client]struct{}
+	closed bool
+	// wg counts live write pumps, so Close can wait for close frames to go out
+	wg sync.WaitGroup
+}
+
+func NewHub() *Hub {
+	return &Hub{rooms: make(map[string]map[*Client]struct{})}
+}
+
+// Join adds c to its room. It fails once shutdown has started, so no
+// connection can slip in after Close has taken its snapshot.
+func (h *Hub) Join(c *Client) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return errHubClosed
+	}
+	if h.rooms[c.room] == nil {
+		h.rooms[c.room] = make(map[*Client]struct{})
+	}
+	h.rooms[c.room][c] = struct{}{}
+	h.wg.Add(1)
+
+	return nil
+}
+
+// Leave removes c and closes its send channel, which tells its write pump
+// to send a close frame and exit. Calling it for a removed client is a no-op.
+func (h *Hub) Leave(c *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.removeLocked(c)
+}
+
+func (h *Hub) removeLocked(c *Client) {
+	clients := h.rooms[c.room]
+	if _, ok := clients[c]; !ok {
+		return
+	}
+	delete(clients, c)
+	if len(clients) == 0 {
+		delete(h.rooms, c.room)
+	}
+	close(c.send)
+}
+
+// Broadcast queues msg for every client in room. A client whose buffer is
+// full is too slow to keep up and gets disconnected rather than letting it
+// block everyone else in the room.
+func (h *Hub) Broadcast(room string, msg []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for c := range h.rooms[room] {
+		select {
+		case c.send <- msg:
+		default:
+			h.removeLocked(c)
+		}
+	}
+}
+
+// Count returns how many clients are in room.
+func (h *Hub) Count(room string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.rooms[room])
+}
+
+// Close disconnects every client with a "going away" close frame and waits
+// until their write pumps have finished, or ctx is done.
+func (h *Hub) Close(ctx context.Context) error {
+	h.mu.Lock()
+	h.closed = true
+	for _, clients := range h.rooms {
+		for c := range clients {
+			h.removeLocked(c)
+		}
+	}
+	h.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		h.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"regexp"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+//go:embed index.html
+var indexHTML []byte
+
+var roomName = regexp.MustCompile(`^[a-z0-9-]{1,32}$`)
+
+// The default CheckOrigin only accepts browsers on the same host, which
+// blocks other sites from opening a socket with the user's cookies.
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+// WebSocket Chat: GET /ws/:room?name=... upgrades to a WebSocket and joins a
+// room; every message is broadcast to the room. Connections are kept alive
+// with ping/pong and closed cleanly with a close frame on shutdown.
+func main() {
+	e := echo.New()
+	e.Use(middleware.Logger())
+
+	hub := NewHub()
+
+	e.GET("/", func(c echo.Context) error {
+		return c.HTMLBlob(http.StatusOK, indexHTML)
+	})
+
+	e.GET("/rooms/:room", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, echo.Map{"room": c.Param("room"), "clients": hub.Count(c.Param("room"))})
+	})
+
+	e.GET("/ws/:room", func(c echo.Context) error {
+		room := c.Param("room")
+		if !roomName.MatchString(room) {
+			return echo.NewHTTPError(http.StatusBadRequest, "room must be 1-32 of a-z, 0-9 and -")
+		}
+		name := c.QueryParam("name")
+		if name == "" {
+			name = "anonymous"
+		}
+
+		// Upgrade writes its own error response on failure, so there is
+		// nothing left for Echo to send.
+		conn, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
+		if err != nil {
+			return nil
+		}
+
+		client := &Client{hub: hub, conn: conn, send: make(chan []byte, sendBuffer), room: room, name: name}
+		if err := hub.Join(client); err != nil {
+			_ = conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseTryAgainLater, err.Error()), time.Now().Add(writeWait))
+			conn.Close()
+			return nil
+		}
+
+		go client.writePump()
+		client.readPump() // blocks until the client leaves
+		return nil
+	})
+
+	srv, err := config.LoadServer(config.DefaultServer("3000"))
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	srv.Apply(e.Server)
+
+	go func() {
+		if err := e.Start(srv.Addr()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			e.Logger.Fatal(err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// http.Server.Shutdown does not touch hijacked connections, and every
+	// WebSocket is one, so the hub has to close them itself.
+	if err := hub.Close(shutdownCtx); err != nil {
+		e.Logger.Error(err)
+	}
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		e.Logger.Error(err)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import "sync"
+
+// Broker fans events out to subscribers and keeps the last few in memory,
+// so a client that reconnects with Last-Event-ID gets what it missed.
+type Broker struct {
+	mu      sync.Mutex
+	nextID  uint64
+	history []Event // oldest first, at most size entries
+	size    int
+	subs    map[chan Event]struct{}
+}
+
+func NewBroker(historySize int) *Broker {
+	return &Broker{nextID: 1, size: historySize, subs: make(map[chan Event]struct{})}
+}
+
+// Publish assigns the next ID to an event and delivers it. A subscriber
+// whose buffer is full misses the event live, but can recover it from the
+// history on its next reconnect.
+func (b *Broker) Publish(name, data string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ev := Event{ID: b.nextID, Name: name, Data: data}
+	b.nextID++
+
+	b.history = append(b.history, ev)
+	if len(b.history) > b.size {
+		b.history = b.history[len(b.history)-b.size:]
+	}
+
+	for ch := range b.subs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// Subscribe returns the events after lastID still in history, plus a
+// channel for new ones. Both are taken under one lock, so no event falls
+// between the replay and the live stream.
+func (b *Broker) Subscribe(lastID uint64) ([]Event, chan Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var missed []Event
+	if lastID > 0 {
+		for _, ev := range b.history {
+			if ev.ID > lastID {
+				missed = append(missed, ev)
+			}
+		}
+	}
+
+	ch := make(chan Event, 16)
+	b.subs[ch] = struct{}{}
+	return missed, ch
+}
+
+func (b *Broker) Unsubscribe(ch chan Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.subs, ch)
+}
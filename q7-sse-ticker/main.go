@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+//go:embed index.html
+var indexHTML []byte
+
+const (
+	retryDelay    = 3 * time.Second
+	heartbeat     = 15 * time.Second
+	progressStep  = 5
+	progressDelay = 300 * time.Millisecond
+)
+
+// Server-Sent Events: /events/time streams a tick every second to every
+// client and replays missed ticks after a reconnect; /events/progress streams
+// one job's progress and resumes where it left off.
+func main() {
+	e := echo.New()
+	e.Use(middleware.Logger())
+
+	broker := NewBroker(100)
+	go tick(context.Background(), broker)
+
+	e.GET("/", func(c echo.Context) error {
+		return c.HTMLBlob(http.StatusOK, indexHTML)
+	})
+
+	e.GET("/events/time", func(c echo.Context) error {
+		lastID, err := lastEventID(c)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Last-Event-ID must be a number")
+		}
+
+		missed, events := broker.Subscribe(lastID)
+		defer broker.Unsubscribe(events)
+
+		if err := startStream(c, retryDelay); err != nil {
+			return nil
+		}
+		for _, ev := range missed {
+			if err := writeEvent(c, ev); err != nil {
+				return nil
+			}
+		}
+
+		ping := time.NewTicker(heartbeat)
+		defer ping.Stop()
+
+		// once streaming, errors mean the client is gone: just return
+		for {
+			select {
+			case <-c.Request().Context().Done():
+				return nil
+			case ev := <-events:
+				if err := writeEvent(c, ev); err != nil {
+					return nil
+				}
+			case <-ping.C:
+				if err := writeComment(c, "ping"); err != nil {
+					return nil
+				}
+			}
+		}
+	})
+
+	e.GET("/events/progress", progress(progressDelay))
+
+	srv, err := config.LoadServer(config.DefaultServer("3000"))
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	srv.Apply(e.Server)
+
+	if err := e.Start(srv.Addr()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		e.Logger.Fatal(err)
+	}
+}
+
+// progress streams one job from 5% to 100%, one step every delay. The
+// event ID is the percentage, so a reconnect resumes after the last one
+// the client saw.
+func progress(delay time.Duration) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		lastID, err := lastEventID(c)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Last-Event-ID must be a number")
+		}
+
+		// 204 is the one response that stops EventSource from reconnecting
+		if lastID >= 100 {
+			return c.NoContent(http.StatusNoContent)
+		}
+
+		if err := startStream(c, retryDelay); err != nil {
+			return nil
+		}
+
+		// resume at the step after lastID; rounding down first keeps a
+		// non-step ID like 98 on the steps, so 100 and "done" still come
+		for pct := lastID - lastID%progressStep + progressStep; pct <= 100; pct += progressStep {
+			select {
+			case <-c.Request().Context().Done():
+				return nil
+			case <-time.After(delay):
+			}
+
+			name := "progress"
+			if pct == 100 {
+				name = "done"
+			}
+			data := fmt.Sprintf(`{"percent":%d}`, pct)
+			if err := writeEvent(c, Event{ID: pct, Name: name, Data: data}); err != nil {
+				return nil
+			}
+		}
+		return nil
+	}
+}
+
+// tick publishes the current time every second until ctx is done.
+func tick(ctx context.Context, b *Broker) {
+	t := time.NewTicker(time.Second)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-t.C:
+			b.Publish("tick", fmt.Sprintf(`{"time":%q}`, now.UTC().Format(time.RFC3339)))
+		}
+	}
+}
+
+// lastEventID reads the ID a reconnecting EventSource sends in the
+// Last-Event-ID header. The query parameter covers the first connection,
+// where browsers cannot set headers, and manual testing with curl.
+func lastEventID(c echo.Context) (uint64, error) {
+	v := c.Request().Header.Get("Last-Event-ID")
+	if v == "" {
+		v = c.QueryParam("lastEventId")
+	}
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(v, 10, 64)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// eventIDs matches the id and name of each event in an SSE body.
+var eventIDs = regexp.MustCompile(`(?m)^id: (\d+)\nevent: (\w+)$`)
+
+func TestProgressResume(t *testing.T) {
+	e := echo.New()
+	e.GET("/events/progress", progress(0))
+
+	tests := []struct {
+		lastID string
+		want   []string // "id:name" of each event
+	}{
+		{"85", []string{"90:progress", "95:progress", "100:done"}},
+		// not on a step: round down, but still finish at 100
+		{"98", []string{"100:done"}},
+		{"97", []string{"100:done"}},
+		{"91", []string{"95:progress", "100:done"}},
+	}
+	for _, tt := range tests {
+		t.Run("Last-Event-ID "+tt.lastID, func(t *testing.T) {
+			if got := progressEvents(t, e, tt.lastID); !slices.Equal(got, tt.want) {
+				t.Errorf("events = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	for _, lastID := range []string{"", "0"} {
+		got := progressEvents(t, e, lastID)
+		if len(got) != 20 || got[0] != "5:progress" || got[19] != "100:done" {
+			t.Errorf("Last-Event-ID %q: events = %v, want 5 to 100 by 5", lastID, got)
+		}
+	}
+}
+
+// progressEvents runs one /events/progress request and returns the
+// "id:name" of each event it streamed.
+func progressEvents(t *testing.T, e *echo.Echo, lastID string) []string {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/events/progress", nil)
+	if lastID != "" {
+		req.Header.Set("Last-Event-ID", lastID)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d", rec.Code)
+	}
+
+	var got []string
+	for _, m := range eventIDs.FindAllStringSubmatch(rec.Body.String(), -1) {
+		got = append(got, m[1]+":"+m[2])
+	}
+	return got
+}
+
+func TestProgressDone(t *testing.T) {
+	e := echo.New()
+	e.GET("/events/progress", progress(0))
+
+	for _, id := range []string{"100", "101", "18446744073709551615"} {
+		req := httptest.NewRequest(http.MethodGet, "/events/progress", nil)
+		req.Header.Set("Last-Event-ID", id)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("Last-Event-ID %s: status %d, want 204 so EventSource stops", id, rec.Code)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/events/progress?lastEventId=nope", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "Last-Event-ID") {
+		t.Errorf("bad ID: %d %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Event is one Server-Sent Event. On the wire it looks like:
+//
+//	id: 42
+//	event: tick
+//	data: {"time":"..."}
+//	<blank line>
+type Event struct {
+	ID   uint64
+	Name string
+	Data string
+}
+
+// startStream sends the SSE headers and the reconnect delay. It must run
+// before the first event: once the body starts, headers cannot change.
+func startStream(c echo.Context, retry time.Duration) error {
+	h := c.Response().Header()
+	h.Set(echo.HeaderContentType, "text/event-stream")
+	h.Set(echo.HeaderCacheControl, "no-cache")
+	h.Set(echo.HeaderConnection, "keep-alive")
+	// nginx buffers responses by default, which holds events back
+	h.Set("X-Accel-Buffering", "no")
+	c.Response().WriteHeader(http.StatusOK)
+
+	// The server's WriteTimeout would end every stream after 10s. Streams
+	// are ended by the client or by the context instead.
+	_ = http.NewResponseController(c.Response()).SetWriteDeadline(time.Time{})
+
+	// retry tells EventSource how long to wait before reconnecting
+	if _, err := fmt.Fprintf(c.Response(), "retry: %d\n\n", retry.Milliseconds()); err != nil {
+		return err
+	}
+	c.Response().Flush()
+	return nil
+}
+
+// writeEvent writes ev and flushes it to the client right away. Multi-line
+// data needs one "data:" line per line, or the event is cut at the first \n.
+func writeEvent(c echo.Context, ev Event) error {
+	var b strings.Builder
+	if ev.ID != 0 {
+		fmt.Fprintf(&b, "id: %d\n", ev.ID)
+	}
+	if ev.Name != "" {
+		fmt.Fprintf(&b, "event: %s\n", ev.Name)
+	}
+	for _, line := range strings.Split(ev.Data, "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+
+	if _, err := c.Response().Write([]byte(b.String())); err != nil {
+		return err
+	}
+	c.Response().Flush()
+	return nil
+}
+
+// writeComment sends a line EventSource ignores. Sent periodically, it keeps
+// proxies from closing an idle stream and detects dead clients sooner.
+func writeComment(c echo.Context, text string) error {
+	if _, err := fmt.Fprintf(c.Response(), ": %s\n\n", text); err != nil {
+		return err
+	}
+	c.Response().Flush()
+	return nil
+}
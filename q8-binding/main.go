@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	"github.com/labstack/echo/v4"
+)
+
+// ProductQuery pulls from three sources at once. Each field says where it
+// comes from; a field without a tag for a source is ignored by that source.
+type ProductQuery struct {
+	Category string   `param:"category" json:"category"`
+	Search   string   `query:"q" json:"q"`
+	Page     int      `query:"page" json:"page"`
+	PerPage  int      `query:"per_page" json:"per_page"`
+	Sort     string   `query:"sort" json:"sort"`
+	Tags     []string `query:"tag" json:"tags"` // ?tag=a&tag=b
+	InStock  bool     `query:"in_stock" json:"in_stock"`
+
+	RequestID string `header:"X-Request-Id" json:"request_id"`
+	Language  string `header:"Accept-Language" json:"language"`
+}
+
+// ReviewForm is a classic HTML form post (application/x-www-form-urlencoded
+// or multipart/form-data) to /products/:category/reviews.
+type ReviewForm struct {
+	Category  string   `param:"category" json:"category"`
+	Rating    int      `form:"rating" json:"rating"`
+	Text      string   `form:"text" json:"text"`
+	Tags      []string `form:"tags" json:"tags"` // <input name="tags"> repeated, e.g. checkboxes
+	Recommend bool     `form:"recommend" json:"recommend"`
+	RequestID string   `header:"X-Request-Id" json:"request_id"`
+}
+
+// Form and Query Binding: path params, query params, form fields and
+// headers bound into structs with echo tags, with defaults and slices.
+func main() {
+	e := echo.New()
+	binder := &echo.DefaultBinder{}
+
+	e.GET("/products/:category", func(c echo.Context) error {
+		// Defaults go in before binding: the binder only overwrites fields
+		// whose key is present in the request.
+		q := ProductQuery{Page: 1, PerPage: 20, Sort: "relevance"}
+
+		// c.Bind covers path, query (GET/DELETE/HEAD only) and body, but
+		// never headers, so those are a second, explicit step.
+		if err := c.Bind(&q); err != nil {
+			return err
+		}
+		if err := binder.BindHeaders(c, &q); err != nil {
+			return err
+		}
+
+		if q.Page < 1 || q.PerPage < 1 || q.PerPage > 100 {
+			return echo.NewHTTPError(http.StatusBadRequest, "page must be >= 1 and per_page between 1 and 100")
+		}
+		return c.JSON(http.StatusOK, q)
+	})
+
+	e.POST("/products/:category/reviews", func(c echo.Context) error {
+		form := ReviewForm{Rating: 5}
+
+		// For POST, c.Bind skips the query string on purpose (a body field and
+		// a query field with one name would otherwise fight), so it binds
+		// path params and the form body here.
+		if err := c.Bind(&form); err != nil {
+			return err
+		}
+		if err := binder.BindHeaders(c, &form); err != nil {
+			return err
+		}
+
+		if form.Rating < 1 || form.Rating > 5 {
+			return echo.NewHTTPError(http.StatusBadRequest, "rating must be between 1 and 5")
+		}
+		return c.JSON(http.StatusCreated, form)
+	})
+
+	// The fluent ValueBinder does the same without struct tags and is handy
+	// for handlers that read a few parameters. It also handles
+	// comma-separated lists and reports every bad parameter at once.
+	e.GET("/fluent", func(c echo.Context) error {
+		var (
+			ids     []int64
+			fields  []string
+			limit   = 10
+			include bool
+		)
+
+		err := echo.QueryParamsBinder(c).
+			FailFast(false).
+			BindWithDelimiter("ids", &ids, ","). // ?ids=1,2,3 or ?ids=1&ids=2
+			BindWithDelimiter("fields", &fields, ",").
+			Int("limit", &limit).
+			Bool("include_deleted", &include).
+			BindErrors()
+		if len(err) > 0 {
+			msgs := make([]string, 0, len(err))
+			for _, e := range err {
+				var be *echo.BindingError
+				if errors.As(e, &be) {
+					msgs = append(msgs, be.Field+": invalid value "+strings.Join(be.Values, ","))
+				}
+			}
+			return echo.NewHTTPError(http.StatusBadRequest, strings.Join(msgs, "; "))
+		}
+
+		return c.JSON(http.StatusOK, echo.Map{
+			"ids":             ids,
+			"fields":          fields,
+			"limit":           limit,
+			"include_deleted": include,
+		})
+	})
+
+	srv, err := config.LoadServer(config.DefaultServer("3000"))
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	srv.Apply(e.Server)
+
+	if err := e.Start(srv.Addr()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		e.Logger.Fatal(err)
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	sharedmw "github.com/jabeedhexanovamedia/go-echo-practice/pkg/middleware"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+type User struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Links are built with e.Reverse, so they follow the routes if paths change
+	Links map[string]string `json:"links"`
+}
+
+var (
+	mu    sync.RWMutex
+	users = map[int]string{1: "John", 2: "Jane"}
+)
+
+// Route names used with e.Reverse. Keeping them as constants means a typo
+// is a compile error instead of an empty URL at runtime.
+const (
+	routeUsers      = "users.list"
+	routeUser       = "users.get"
+	routeUserPosts  = "users.posts"
+	routeAdminStats = "admin.stats"
+)
+
+// Route Groups: /api with nested /v1 and /v2 groups, /api/v1/account and
+// /admin guarded by group-level middleware, and named routes turned back
+// into URLs with e.Reverse.
+func main() {
+	e := echo.New()
+	e.Use(middleware.Logger())
+
+	userToken := envOr("USER_TOKEN", "user-token")
+	adminToken := envOr("ADMIN_TOKEN", "admin-token")
+
+	// middleware on /api runs for every route below it, nested groups included
+	api := e.Group("/api", middleware.CORS())
+
+	v1 := api.Group("/v1")
+	v1.GET("/users", listUsers).Name = routeUsers
+	v1.GET("/users/:id", getUser).Name = routeUser
+	v1.GET("/users/:id/posts", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, []string{})
+	}).Name = routeUserPosts
+	v1.POST("/users", createUser)
+
+	// a nested group adds its own middleware on top of its parent's
+	account := v1.Group("/account", bearer(userToken))
+	account.GET("", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, echo.Map{"user": "the token owner"})
+	})
+
+	// v2 can change response shapes without breaking v1 clients
+	v2 := api.Group("/v2")
+	v2.GET("/users", func(c echo.Context) error {
+		list := usersList(c)
+		return c.JSON(http.StatusOK, echo.Map{"data": list, "count": len(list)})
+	})
+
+	// /admin is a separate top-level group with a different credential
+	admin := e.Group("/admin", bearer(adminToken))
+	admin.GET("/stats", func(c echo.Context) error {
+		mu.RLock()
+		defer mu.RUnlock()
+		return c.JSON(http.StatusOK, echo.Map{"users": len(users)})
+	}).Name = routeAdminStats
+
+	reports := admin.Group("/reports")
+	reports.GET("/daily", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, echo.Map{"report": "daily", "stats": c.Echo().Reverse(routeAdminStats)})
+	})
+
+	// every registered route, its method and name
+	e.GET("/routes", func(c echo.Context) error {
+		routes := make([]*echo.Route, 0)
+		for _, r := range c.Echo().Routes() {
+			// groups with middleware register catch-all 404 routes; hide them
+			if r.Method != echo.RouteNotFound {
+				routes = append(routes, r)
+			}
+		}
+		sort.Slice(routes, func(i, j int) bool { return routes[i].Path < routes[j].Path })
+		return c.JSON(http.StatusOK, routes)
+	})
+
+	srv, err := config.LoadServer(config.DefaultServer("3000"))
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	srv.Apply(e.Server)
+
+	if err := e.Start(srv.Addr()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		e.Logger.Fatal(err)
+	}
+}
+
+func listUsers(c echo.Context) error {
+	return c.JSON(http.StatusOK, usersList(c))
+}
+
+func getUser(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid user id")
+	}
+	mu.RLock()
+	name, ok := users[id]
+	mu.RUnlock()
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, "user not found")
+	}
+	return c.JSON(http.StatusOK, newUser(c, id, name))
+}
+
+func createUser(c echo.Context) error {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := c.Bind(&req); err != nil || req.Name == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "name is required")
+	}
+
+	mu.Lock()
+	id := len(users) + 1
+	users[id] = req.Name
+	mu.Unlock()
+
+	u := newUser(c, id, req.Name)
+	c.Response().Header().Set(echo.HeaderLocation, u.Links["self"])
+	return c.JSON(http.StatusCreated, u)
+}
+
+func usersList(c echo.Context) []User {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	list := make([]User, 0, len(users))
+	for id, name := range users {
+		list = append(list, newUser(c, id, name))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+// newUser fills in links by route name: e.Reverse substitutes the :id
+// parameter in order, so the URL always matches the registered path.
+func newUser(c echo.Context, id int, name string) User {
+	e := c.Echo()
+	return User{
+		ID:   id,
+		Name: name,
+		Links: map[string]string{
+			"self":       e.Reverse(routeUser, id),
+			"posts":      e.Reverse(routeUserPosts, id),
+			"collection": e.Reverse(routeUsers),
+		},
+	}
+}
+
+// bearer guards a group with a static token in "Authorization: Bearer ...",
+// using the shared net/http check from pkg/middleware.
+func bearer(token string) echo.MiddlewareFunc {
+	return echo.WrapMiddleware(sharedmw.BearerAuth(sharedmw.Keys(token)))
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// Static File Serving: serve /assets from disk with long-lived cache headers,
+// fall back to index.html for client-side (SPA) routes, and answer missing
+// files with a proper 404 page instead of the SPA shell.
+const publicDir = "public"
+
+func main() {
+	e := echo.New()
+	e.HTTPErrorHandler = notFoundHandler(e.DefaultHTTPErrorHandler)
+
+	// JSON API lives next to the static site
+	api := e.Group("/api")
+	api.GET("/hello", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, echo.Map{"message": "hello from the API"})
+	})
+
+	// Assets are safe to cache for a long time when their names change on
+	// every release (app.3f9a1c.js); plain names would need a shorter max-age.
+	assets := e.Group("/assets", cacheControl("public, max-age=31536000, immutable"))
+	assets.Static("/", filepath.Join(publicDir, "assets"))
+
+	e.Use(noCacheHTML)
+
+	// SPA fallback: any other GET that is not a real file gets index.html, so
+	// /about and /settings/profile work on reload. index.html itself must not
+	// be cached or users keep an old shell pointing at old asset names.
+	e.Use(middleware.StaticWithConfig(middleware.StaticConfig{
+		Root:  publicDir,
+		HTML5: true,
+		Skipper: func(c echo.Context) bool {
+			p := c.Request().URL.Path
+			return strings.HasPrefix(p, "/api/") || strings.HasPrefix(p, "/assets/")
+		},
+	}))
+
+	srv, err := config.LoadServer(config.DefaultServer("3000"))
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	srv.Apply(e.Server)
+
+	if err := e.Start(srv.Addr()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		e.Logger.Fatal(err)
+	}
+}
+
+// cacheControl sets a fixed Cache-Control header on every response.
+func cacheControl(value string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Response().Header().Set(echo.HeaderCacheControl, value)
+			return next(c)
+		}
+	}
+}
+
+// noCacheHTML makes browsers revalidate anything that is not an asset or API
+// call, which in practice is the index.html shell.
+func noCacheHTML(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		p := c.Request().URL.Path
+		if !strings.HasPrefix(p, "/assets/") && !strings.HasPrefix(p, "/api/") {
+			c.Response().Header().Set(echo.HeaderCacheControl, "no-cache")
+		}
+		return next(c)
+	}
+}
+
+// notFoundHandler replaces Echo's JSON 404 with the 404.html page for
+// missing files, keeping JSON errors for /api routes.
+func notFoundHandler(fallback echo.HTTPErrorHandler) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		var he *echo.HTTPError
+		if !errors.As(err, &he) || he.Code != http.StatusNotFound ||
+			strings.HasPrefix(c.Request().URL.Path, "/api/") || c.Response().Committed {
+			fallback(err, c)
+			return
+		}
+
+		page, readErr := os.ReadFile(filepath.Join(publicDir, "404.html"))
+		if readErr != nil {
+			fallback(err, c)
+			return
+		}
+
+		// a 404 must not be cached for a year like the asset it replaced
+		c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+		if err := c.HTMLBlob(http.StatusNotFound, page); err != nil {
+			c.Logger().Error(err)
+		}
+	}
+}
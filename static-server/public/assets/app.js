@@ -0,0 +1,31 @@
+// Tiny client-side router: the server always answers unknown paths with
+// index.html, and this script decides what to show for location.pathname.
+const routes = {
+  "/": "Home",
+  "/about": "About this example",
+  "/settings/profile": "Profile settings",
+};
+
+function render() {
+  const title = routes[location.pathname] || "Page not found (client side)";
+  document.getElementById("app").innerHTML = `<h1>${title}</h1>`;
+
+  fetch("/api/hello")
+    .then((res) => res.json())
+    .then((data) => {
+      const p = document.createElement("p");
+      p.textContent = `API says: ${data.message}`;
+      document.getElementById("app").appendChild(p);
+    });
+}
+
+document.addEventListener("click", (e) => {
+  if (e.target.tagName === "A" && e.target.origin === location.origin) {
+    e.preventDefault();
+    history.pushState({}, "", e.target.pathname);
+    render();
+  }
+});
+
+window.addEventListener("popstate", render);
+render();
@@ -0,0 +1,118 @@
+package admin
+
+import (
+	"time"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/apierror"
+	pkglogger "github.com/jabeedhexanovamedia/go-echo-practice/pkg/logger"
+	sharedmw "github.com/jabeedhexanovamedia/go-echo-practice/pkg/middleware"
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/response"
+	"github.com/jabeedhexanovamedia/todo-ap/config"
+	"github.com/jabeedhexanovamedia/todo-ap/logging"
+	"github.com/jabeedhexanovamedia/todo-ap/maintenance"
+	"github.com/labstack/echo/v5"
+)
+
+// defaultLogLevelDuration bounds how long a runtime log level change lasts
+// when the caller does not say, so a forgotten debug level reverts itself.
+const defaultLogLevelDuration = 15 * time.Minute
+
+// RequireToken accepts requests with "Authorization: Bearer <token>". It
+// guards /admin and /debug.
+func RequireToken(token string) echo.MiddlewareFunc {
+	return echo.WrapMiddleware(sharedmw.BearerAuth(sharedmw.Keys(token)))
+}
+
+// Register mounts the /admin group, guarded by ADMIN_TOKEN as a bearer key.
+// Without a token configured the admin routes are not exposed at all.
+func Register(e *echo.Echo, cfg *config.Config, logger *logging.Logger, mode *maintenance.Mode) {
+	if cfg.Auth.AdminToken == "" {
+		return
+	}
+
+	g := e.Group("/admin", RequireToken(cfg.Auth.AdminToken))
+
+	// effective config with secrets masked, to debug which value actually won
+	g.GET("/config", func(c *echo.Context) error {
+		return response.OK(c, cfg.Redacted())
+	})
+
+	g.GET("/log-level", func(c *echo.Context) error {
+		return response.OK(c, map[string]any{"level": logger.Level().String()})
+	})
+
+	// temporarily change the log level, e.g. {"level":"debug","duration":"10m"}
+	g.PUT("/log-level", func(c *echo.Context) error {
+		var req struct {
+			Level    string `json:"level"`
+			Duration string `json:"duration"`
+		}
+		if err := c.Bind(&req); err != nil {
+			return apierror.BadRequest("invalid request payload")
+		}
+
+		level, err := logging.ParseLevel(req.Level)
+		if err != nil {
+			return apierror.BadRequest(err.Error())
+		}
+
+		d := defaultLogLevelDuration
+		if req.Duration != "" {
+			if d, err = time.ParseDuration(req.Duration); err != nil || d < 0 {
+				return apierror.BadRequest("duration must be a duration like 10m")
+			}
+		}
+
+		pkglogger.FromContext(c.Request().Context()).Info("log level changed", "level", level.String(), "duration", d.String())
+		// "duration":"0s" keeps the level until the next change; reverts
+		// is then null
+		var reverts *time.Time
+		if at := logger.SetLevelFor(level, d); !at.IsZero() {
+			at = at.UTC()
+			reverts = &at
+		}
+
+		return response.OK(c, map[string]any{
+			"level":    level.String(),
+			"reverts":  reverts,
+			"duration": d.String(),
+		})
+	})
+	g.GET("/maintenance", func(c *echo.Context) error {
+		return response.OK(c, maintenanceStatus(mode.Status()))
+	})
+
+	// switch maintenance mode, e.g. {"enabled":true,"retry_after":"10m"}
+	// before a migration and {"enabled":false} after it
+	g.PUT("/maintenance", func(c *echo.Context) error {
+		var req struct {
+			Enabled    bool   `json:"enabled"`
+			RetryAfter string `json:"retry_after"`
+		}
+		if err := c.Bind(&req); err != nil {
+			return apierror.BadRequest("invalid request payload")
+		}
+
+		d := cfg.Maintenance.RetryAfter
+		if req.RetryAfter != "" {
+			var err error
+			if d, err = time.ParseDuration(req.RetryAfter); err != nil || d < 0 {
+				return apierror.BadRequest("retry_after must be a duration like 10m")
+			}
+		}
+
+		pkglogger.FromContext(c.Request().Context()).Warn("maintenance mode changed", "enabled", req.Enabled, "retry_after", d.String())
+		return response.OK(c, maintenanceStatus(mode.Set(req.Enabled, d)))
+	})
+}
+
+func maintenanceStatus(s maintenance.Status) map[string]any {
+	out := map[string]any{
+		"enabled":     s.Enabled,
+		"retry_after": s.RetryAfter.String(),
+	}
+	if s.Enabled {
+		out["since"] = s.Since
+	}
+	return out
+}
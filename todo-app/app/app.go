@@ -0,0 +1,56 @@
+// Package app wires the todo-app routes and middleware, so the app can be
+// mounted by other binaries such as cmd/all; main.go is only bootstrap.
+package app
+
+import (
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/buildinfo"
+	pkglogger "github.com/jabeedhexanovamedia/go-echo-practice/pkg/logger"
+	sharedmw "github.com/jabeedhexanovamedia/go-echo-practice/pkg/middleware"
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/response"
+	"github.com/jabeedhexanovamedia/todo-ap/admin"
+	"github.com/jabeedhexanovamedia/todo-ap/config"
+	"github.com/jabeedhexanovamedia/todo-ap/debug"
+	"github.com/jabeedhexanovamedia/todo-ap/logging"
+	"github.com/jabeedhexanovamedia/todo-ap/maintenance"
+	"github.com/jabeedhexanovamedia/todo-ap/web"
+	"github.com/labstack/echo/v5"
+
+	"github.com/labstack/echo/v5/middleware"
+)
+
+// NewServer returns the Echo app with all routes registered.
+func NewServer(cfg *config.Config, logger *logging.Logger) *echo.Echo {
+	e := echo.New()
+	e.Logger = logger.Logger
+	e.HTTPErrorHandler = errorHandler
+	// the ID is set before RequestLogger runs, so every log line carries it
+	e.Use(echo.WrapMiddleware(sharedmw.RequestID))
+	e.Use(middleware.RequestLogger())
+	// handlers and the layers below them log through logger.FromContext
+	e.Use(echo.WrapMiddleware(pkglogger.Middleware(logger.Logger)))
+	// MAINTENANCE_MODE or PUT /admin/maintenance turns everything but
+	// health checks, admin and debug away with 503
+	mode := maintenance.New(cfg.Maintenance)
+	e.Use(mode.Middleware("/healthz", "/admin", "/debug"))
+	// every write endpoint takes JSON; reject anything else up front
+	e.Use(echo.WrapMiddleware(sharedmw.RequireContentType(echo.MIMEApplicationJSON)))
+
+	e.GET("/api", func(c *echo.Context) error {
+		return c.String(200, "Todo API running in "+cfg.AppEnv+" mode")
+	})
+
+	e.GET("/version", func(c *echo.Context) error {
+		return response.OK(c, buildinfo.Get())
+	})
+
+	// liveness for the orchestrator; stays up during maintenance
+	e.GET("/healthz", func(c *echo.Context) error {
+		return response.OK(c, map[string]string{"status": "ok"})
+	})
+
+	admin.Register(e, cfg, logger, mode)
+	debug.Register(e, cfg)
+	web.Register(e)
+
+	return e
+}
@@ -0,0 +1,128 @@
+package app_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/testutil"
+	"github.com/jabeedhexanovamedia/todo-ap/app"
+	"github.com/jabeedhexanovamedia/todo-ap/config"
+	"github.com/jabeedhexanovamedia/todo-ap/logging"
+	"github.com/labstack/echo/v5"
+)
+
+func newServer(t *testing.T, cfg *config.Config) *echo.Echo {
+	t.Helper()
+	logger, err := logging.New(config.LogConfig{Level: "error", Format: "json"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return app.NewServer(cfg, logger)
+}
+
+func TestRoutes(t *testing.T) {
+	e := newServer(t, &config.Config{AppEnv: "test"})
+
+	rec := testutil.Serve(e, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	testutil.AssertJSON(t, rec, `{"status":"ok"}`)
+
+	rec = testutil.Serve(e, httptest.NewRequest(http.MethodGet, "/api", nil))
+	testutil.AssertStatus(t, rec, http.StatusOK)
+	if got := rec.Body.String(); got != "Todo API running in test mode" {
+		t.Errorf("GET /api = %q", got)
+	}
+
+	rec = testutil.Serve(e, httptest.NewRequest(http.MethodGet, "/version", nil))
+	testutil.AssertStatus(t, rec, http.StatusOK)
+
+	// client-side routes get the index.html shell
+	rec = testutil.Serve(e, httptest.NewRequest(http.MethodGet, "/todos/42", nil))
+	testutil.AssertStatus(t, rec, http.StatusOK)
+	testutil.AssertHeader(t, rec, "Cache-Control", "no-cache")
+	if !strings.Contains(rec.Body.String(), "<html") {
+		t.Errorf("GET /todos/42 is not the HTML shell: %.100s", rec.Body)
+	}
+}
+
+// TestRoutedPrefixes checks that paths the router owns answer 404 rather
+// than fall back to the HTML shell, including groups that are not mounted.
+func TestRoutedPrefixes(t *testing.T) {
+	// no admin token and debug off: neither /admin nor /debug is mounted
+	e := newServer(t, &config.Config{AppEnv: "test"})
+	for _, target := range []string{
+		"/admin/config",
+		"/debug/pprof/",
+		"/debug/vars",
+		"/healthz/extra",
+		"/api/nope",
+		"/version/extra",
+		"/assets/missing.js",
+	} {
+		t.Run(target, func(t *testing.T) {
+			rec := testutil.Serve(e, httptest.NewRequest(http.MethodGet, target, nil))
+			testutil.AssertError(t, rec, http.StatusNotFound, "not_found")
+			if cc := rec.Header().Get("Cache-Control"); cc != "" {
+				t.Errorf("Cache-Control = %q on a 404", cc)
+			}
+		})
+	}
+}
+
+func TestRequireJSON(t *testing.T) {
+	e := newServer(t, &config.Config{AppEnv: "test"})
+
+	req := httptest.NewRequest(http.MethodPost, "/api", strings.NewReader("title=milk"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	testutil.AssertError(t, testutil.Serve(e, req), http.StatusUnsupportedMediaType, "unsupported_media_type")
+}
+
+func TestMaintenance(t *testing.T) {
+	e := newServer(t, &config.Config{
+		AppEnv:      "test",
+		Auth:        config.AuthConfig{AdminToken: "s3cret"},
+		Maintenance: config.MaintenanceConfig{Enabled: true, RetryAfter: 90 * time.Second},
+	})
+
+	rec := testutil.Serve(e, httptest.NewRequest(http.MethodGet, "/api", nil))
+	testutil.AssertError(t, rec, http.StatusServiceUnavailable, "service_unavailable")
+	testutil.AssertHeader(t, rec, "Retry-After", "90")
+
+	testutil.AssertStatus(t, testutil.Serve(e, httptest.NewRequest(http.MethodGet, "/healthz", nil)), http.StatusOK)
+
+	// admin stays reachable, so maintenance can be switched off again
+	req := testutil.JSONRequest(t, http.MethodPut, "/admin/maintenance", map[string]any{"enabled": false})
+	rec = testutil.Serve(e, req)
+	testutil.AssertError(t, rec, http.StatusUnauthorized, "unauthorized")
+	testutil.AssertHeader(t, rec, "WWW-Authenticate", `Bearer realm="api"`)
+
+	req = testutil.JSONRequest(t, http.MethodPut, "/admin/maintenance", map[string]any{"enabled": false})
+	req.Header.Set("Authorization", "Bearer s3cret")
+	testutil.AssertStatus(t, testutil.Serve(e, req), http.StatusOK)
+
+	testutil.AssertStatus(t, testutil.Serve(e, httptest.NewRequest(http.MethodGet, "/api", nil)), http.StatusOK)
+}
+
+func TestLogLevel(t *testing.T) {
+	e := newServer(t, &config.Config{AppEnv: "test", Auth: config.AuthConfig{AdminToken: "s3cret"}})
+	put := func(body map[string]string) *httptest.ResponseRecorder {
+		req := testutil.JSONRequest(t, http.MethodPut, "/admin/log-level", body)
+		req.Header.Set("Authorization", "Bearer s3cret")
+		return testutil.Serve(e, req)
+	}
+
+	rec := put(map[string]string{"level": "debug", "duration": "10m"})
+	testutil.AssertStatus(t, rec, http.StatusOK)
+	got := testutil.DecodeJSON[map[string]any](t, rec)
+	if reverts, _ := got["reverts"].(string); reverts == "" {
+		t.Errorf("reverts = %v, want a time", got["reverts"])
+	}
+
+	// a zero duration never reverts
+	rec = put(map[string]string{"level": "warn", "duration": "0s"})
+	testutil.AssertJSON(t, rec, `{"level":"WARN","reverts":null,"duration":"0s"}`)
+
+	testutil.AssertError(t, put(map[string]string{"level": "debug", "duration": "-1m"}), http.StatusBadRequest, "bad_request")
+}
@@ -0,0 +1,38 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/apierror"
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/response"
+	"github.com/labstack/echo/v5"
+)
+
+// errorHandler renders every error in the shared envelope, the same shape
+// q2 uses:
+//
+//	{"error":{"code":"unsupported_media_type","message":"Content-Type must be application/json"}}
+//
+// Handlers return *apierror.Error for expected failures. Echo's own errors
+// keep their status and message. Anything else is a 500, logged here and
+// hidden from the client.
+func errorHandler(c *echo.Context, err error) {
+	if r, _ := echo.UnwrapResponse(c.Response()); r != nil && r.Committed {
+		return
+	}
+
+	e := apierror.From(err)
+	var he *echo.HTTPError
+	if errors.As(err, &he) && he.Message != "" {
+		e = apierror.New(e.Status, he.Message)
+	}
+
+	if e.Status >= http.StatusInternalServerError {
+		c.Logger().Error("request failed", "error", err)
+	}
+
+	if err := response.Error(c, e.Status, e.Code, e.Message, e.Fields); err != nil {
+		c.Logger().Error("failed to send error response", "error", err)
+	}
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+const awsLoadTimeout = 10 * time.Second
+
+// loadAWSSources pulls values from SSM Parameter Store (AWS_SSM_PREFIX) and
+// Secrets Manager (AWS_SECRET_ID) into the process env. Values already set in
+// the environment or .env are never overwritten, so env always wins.
+func loadAWSSources() error {
+	prefix := os.Getenv("AWS_SSM_PREFIX")
+	secretID := os.Getenv("AWS_SECRET_ID")
+	if prefix == "" && secretID == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), awsLoadTimeout)
+	defer cancel()
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("load aws config: %w", err)
+	}
+
+	values := map[string]string{}
+
+	if prefix != "" {
+		params, err := fetchSSMParameters(ctx, ssm.NewFromConfig(awsCfg), prefix)
+		if err != nil {
+			return err
+		}
+		for k, v := range params {
+			values[k] = v
+		}
+	}
+
+	// Secrets Manager is applied after SSM so a secret overrides a plain parameter
+	if secretID != "" {
+		secrets, err := fetchSecret(ctx, secretsmanager.NewFromConfig(awsCfg), secretID)
+		if err != nil {
+			return err
+		}
+		for k, v := range secrets {
+			values[k] = v
+		}
+	}
+
+	for k, v := range values {
+		if _, exists := os.LookupEnv(k); exists {
+			continue
+		}
+		if err := os.Setenv(k, v); err != nil {
+			return fmt.Errorf("set %s: %w", k, err)
+		}
+	}
+
+	return nil
+}
+
+// fetchSSMParameters reads every parameter under prefix, keyed by the last
+// path segment: /todo-app/prod/DB_URI becomes DB_URI.
+func fetchSSMParameters(ctx context.Context, client *ssm.Client, prefix string) (map[string]string, error) {
+	params := map[string]string{}
+
+	paginator := ssm.NewGetParametersByPathPaginator(client, &ssm.GetParametersByPathInput{
+		Path:           &prefix,
+		Recursive:      aws.Bool(true),
+		WithDecryption: aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("get ssm parameters by path %q: %w", prefix, err)
+		}
+		for _, p := range page.Parameters {
+			if p.Name == nil || p.Value == nil {
+				continue
+			}
+			params[path.Base(*p.Name)] = *p.Value
+		}
+	}
+
+	return params, nil
+}
+
+// fetchSecret reads a Secrets Manager secret stored as a flat JSON object,
+// e.g. {"DB_URI":"postgres://...","AUTH_JWT_SECRET":"..."}.
+func fetchSecret(ctx context.Context, client *secretsmanager.Client, secretID string) (map[string]string, error) {
+	out, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: &secretID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get secret %q: %w", secretID, err)
+	}
+	if out.SecretString == nil {
+		return nil, fmt.Errorf("secret %q has no string value", secretID)
+	}
+
+	secrets := map[string]string{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(*out.SecretString)), &secrets); err != nil {
+		return nil, fmt.Errorf("decode secret %q: %w", secretID, err)
+	}
+
+	return secrets, nil
+}
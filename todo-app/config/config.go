@@ -1,16 +1,65 @@
 package config
 
 import (
+	"errors"
+	"flag"
+	"fmt"
 	"log"
 	"os"
+	"time"
 
+	sharedconfig "github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	AppEnv string
-	Port   string
-	DBURI  string
+	AppEnv string       `json:"app_env"`
+	Server ServerConfig `json:"server"`
+	DB     DBConfig     `json:"db"`
+	Auth   AuthConfig   `json:"auth"`
+	Log    LogConfig    `json:"log"`
+
+	Maintenance MaintenanceConfig `json:"maintenance"`
+	Debug       DebugConfig       `json:"debug"`
+}
+
+// ServerConfig is the shared listen address and timeout settings.
+type ServerConfig = sharedconfig.Server
+
+type DBConfig struct {
+	URI      string `json:"uri"`
+	MaxConns int    `json:"max_conns"`
+
+	// Components parsed from URI after DB_* overrides are merged in
+	Host    string `json:"host,omitempty"`
+	Port    string `json:"port,omitempty"`
+	User    string `json:"user,omitempty"`
+	Name    string `json:"name,omitempty"`
+	SSLMode string `json:"sslmode,omitempty"`
+}
+
+type AuthConfig struct {
+	JWTSecret  string `json:"jwt_secret"`
+	AdminToken string `json:"admin_token"`
+}
+
+type LogConfig struct {
+	Level  string `json:"level"`
+	Format string `json:"format"`
+}
+
+// DebugConfig turns on the /debug group with pprof and expvar. It is
+// guarded by ADMIN_TOKEN, like /admin.
+type DebugConfig struct {
+	Enabled bool `json:"enabled"`
+}
+
+// MaintenanceConfig is the maintenance mode the app starts in; the admin
+// API can switch it at runtime.
+type MaintenanceConfig struct {
+	Enabled bool `json:"enabled"`
+	// RetryAfter is sent to clients turned away while it is on.
+	RetryAfter time.Duration `json:"retry_after"`
 }
 
 func LoadConfig() *Config {
@@ -25,15 +74,76 @@ func LoadConfig() *Config {
 
 	_ = godotenv.Load()
 
+	// Pull SSM / Secrets Manager values when running in AWS; env still wins
+	if err := loadAWSSources(); err != nil {
+		log.Fatalf("failed to load config from AWS: %v", err)
+	}
+
+	appEnv, p, err := profileFor(getEnv("APP_ENV", EnvDevelopment))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	env := &envLoader{}
+	d := defaults(appEnv, p)
+
+	server, err := sharedconfig.LoadServer(d.Server)
+	env.add(err)
+
 	cfg := &Config{
-		AppEnv: getEnv("APP_ENV", "development"),
-		Port:   getEnv("PORT", "8080"),
-		DBURI:  getEnv("DB_URI", ""),
+		AppEnv: appEnv,
+		Server: server,
+		DB: DBConfig{
+			// DATABASE_URL is what Heroku, Render and Railway inject
+			URI:      getEnv("DB_URI", getEnv("DATABASE_URL", d.DB.URI)),
+			MaxConns: env.Int("DB_MAX_CONNS", d.DB.MaxConns),
+		},
+		Auth: AuthConfig{
+			JWTSecret:  getEnv("AUTH_JWT_SECRET", d.Auth.JWTSecret),
+			AdminToken: getEnv("ADMIN_TOKEN", d.Auth.AdminToken),
+		},
+		Log: LogConfig{
+			Level:  getEnv("LOG_LEVEL", d.Log.Level),
+			Format: getEnv("LOG_FORMAT", d.Log.Format),
+		},
+		Maintenance: MaintenanceConfig{
+			Enabled:    env.Bool("MAINTENANCE_MODE", d.Maintenance.Enabled),
+			RetryAfter: env.Duration("MAINTENANCE_RETRY_AFTER", d.Maintenance.RetryAfter),
+		},
+		Debug: DebugConfig{
+			Enabled: env.Bool("DEBUG_ENDPOINTS", d.Debug.Enabled),
+		},
+	}
+
+	if err := env.Err(); err != nil {
+		log.Fatalf("invalid environment:\n%v", err)
+	}
+
+	// Command-line flags have the final say over env and .env values
+	printConfig, err := applyFlags(cfg, os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		log.Fatalf("invalid command-line flags: %v", err)
+	}
+
+	// Runs after flags so --db-uri is split into components too. Neither
+	// env nor a flag changed the URI when it still is the profile's.
+	fromProfile := os.Getenv("DB_URI") == "" && os.Getenv("DATABASE_URL") == "" && cfg.DB.URI == d.DB.URI
+	if cfg.DB, err = resolveDB(cfg.DB, fromProfile); err != nil {
+		log.Fatal(err)
+	}
+
+	// Print before validation so a missing value is visible too
+	if printConfig {
+		fmt.Println(cfg)
+		os.Exit(0)
 	}
 
 	// Fail fast if critical config missing
-	if cfg.DBURI == "" {
-		log.Fatal("DB_URI is required but not set")
+	if err := p.validate(cfg); err != nil {
+		log.Fatalf("invalid config for %s:\n%v", cfg.AppEnv, err)
 	}
 
 	return cfg
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveDB(t *testing.T) {
+	tests := []struct {
+		name        string
+		uri         string
+		fromProfile bool
+		env         map[string]string
+		wantURI     string
+		wantHost    string
+		wantErr     string
+	}{
+		{
+			name: "sqlite default without overrides", uri: "sqlite://todo.db", fromProfile: true,
+			wantURI: "sqlite://todo.db",
+		},
+		{
+			name: "sqlite default with overrides", uri: "sqlite://todo.db", fromProfile: true,
+			env:     map[string]string{"DB_HOST": "db.internal", "DB_USER": "app", "DB_NAME": "todos"},
+			wantURI: "postgres://app@db.internal:5432/todos", wantHost: "db.internal",
+		},
+		{
+			name: "explicit sqlite with overrides", uri: "sqlite://other.db",
+			env:     map[string]string{"DB_HOST": "db.internal", "DB_PASSWORD": "pw"},
+			wantErr: "DB_HOST, DB_PASSWORD set, but DB_URI is SQLite (sqlite://other.db)",
+		},
+		{
+			name: "postgres with overrides", uri: "postgres://u:p@localhost:5432/app?sslmode=disable",
+			env:     map[string]string{"DB_HOST": "db.internal", "DB_PORT": "6432", "DB_SSLMODE": "require"},
+			wantURI: "postgres://u:p@db.internal:6432/app?sslmode=require", wantHost: "db.internal",
+		},
+		{
+			name:    "overrides only",
+			env:     map[string]string{"DB_HOST": "db.internal"},
+			wantURI: "postgres://db.internal:5432", wantHost: "db.internal",
+		},
+		{
+			name: "nothing set",
+		},
+		{
+			name: "not a URL", uri: "localhost/app",
+			wantErr: "DB_URI must look like",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, k := range dbOverrides {
+				t.Setenv(k, tt.env[k])
+			}
+
+			got, err := resolveDB(DBConfig{URI: tt.uri}, tt.fromProfile)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.URI != tt.wantURI || got.Host != tt.wantHost {
+				t.Errorf("URI, Host = %q, %q; want %q, %q", got.URI, got.Host, tt.wantURI, tt.wantHost)
+			}
+		})
+	}
+}
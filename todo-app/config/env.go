@@ -0,0 +1,48 @@
+package config
+
+import (
+	"errors"
+	"time"
+
+	sharedconfig "github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+)
+
+// envLoader records getter errors and passes values through, so LoadConfig
+// can report every bad variable at once instead of stopping at the first.
+type envLoader struct {
+	errs []error
+}
+
+func (l *envLoader) Int(key string, defaultValue int) int {
+	v, err := sharedconfig.GetEnvInt(key, defaultValue)
+	l.add(err)
+	return v
+}
+
+func (l *envLoader) Bool(key string, defaultValue bool) bool {
+	v, err := sharedconfig.GetEnvBool(key, defaultValue)
+	l.add(err)
+	return v
+}
+
+func (l *envLoader) Duration(key string, defaultValue time.Duration) time.Duration {
+	v, err := sharedconfig.GetEnvDuration(key, defaultValue)
+	l.add(err)
+	return v
+}
+
+func (l *envLoader) Slice(key string, defaultValue []string) []string {
+	v, err := sharedconfig.GetEnvSlice(key, defaultValue)
+	l.add(err)
+	return v
+}
+
+func (l *envLoader) add(err error) {
+	if err != nil {
+		l.errs = append(l.errs, err)
+	}
+}
+
+func (l *envLoader) Err() error {
+	return errors.Join(l.errs...)
+}
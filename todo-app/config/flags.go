@@ -0,0 +1,36 @@
+package config
+
+import (
+	"flag"
+	"os"
+)
+
+// applyFlags overrides env values with command-line flags, so a quick local
+// run can be `go run . --port 9000 --db-uri postgres://...` without a .env.
+// Only flags that are explicitly passed win over env and defaults.
+// It reports whether --print-config was requested.
+func applyFlags(cfg *Config, args []string) (bool, error) {
+	fs := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+
+	port := fs.String("port", cfg.Server.Port, "HTTP port to listen on (overrides SERVER_PORT)")
+	dbURI := fs.String("db-uri", cfg.DB.URI, "database connection URI (overrides DB_URI)")
+	logLevel := fs.String("log-level", cfg.Log.Level, "log level: debug, info, warn, error (overrides LOG_LEVEL)")
+	printConfig := fs.Bool("print-config", false, "print the effective config with secrets masked and exit")
+
+	if err := fs.Parse(args); err != nil {
+		return false, err
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "port":
+			cfg.Server.Port = *port
+		case "db-uri":
+			cfg.DB.URI = *dbURI
+		case "log-level":
+			cfg.Log.Level = *logLevel
+		}
+	})
+
+	return *printConfig, nil
+}
@@ -0,0 +1,85 @@
+package config
+
+import "time"
+
+// Option customises a Config built with New.
+type Option func(*Config)
+
+// Default returns the development profile defaults without reading env
+// vars, .env files or flags.
+func Default() *Config {
+	cfg := defaults(EnvDevelopment, profiles[EnvDevelopment])
+	return &cfg
+}
+
+// New builds a Config programmatically from Default plus opts, so tests and
+// examples don't have to manipulate process env vars. It does not validate.
+func New(opts ...Option) *Config {
+	cfg := Default()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// defaults is the single source of default values shared by LoadConfig and New.
+func defaults(appEnv string, p profile) Config {
+	return Config{
+		AppEnv: appEnv,
+		Server: p.server(),
+		DB: DBConfig{
+			URI:      p.DBURI,
+			MaxConns: 10,
+		},
+		Log: LogConfig{
+			Level:  p.LogLevel,
+			Format: p.LogFormat,
+		},
+		Maintenance: MaintenanceConfig{
+			RetryAfter: 5 * time.Minute,
+		},
+	}
+}
+
+func WithAppEnv(appEnv string) Option {
+	return func(c *Config) { c.AppEnv = appEnv }
+}
+
+func WithPort(port string) Option {
+	return func(c *Config) { c.Server.Port = port }
+}
+
+func WithServer(server ServerConfig) Option {
+	return func(c *Config) { c.Server = server }
+}
+
+func WithDBURI(uri string) Option {
+	return func(c *Config) { c.DB.URI = uri }
+}
+
+func WithDB(db DBConfig) Option {
+	return func(c *Config) { c.DB = db }
+}
+
+func WithJWTSecret(secret string) Option {
+	return func(c *Config) { c.Auth.JWTSecret = secret }
+}
+
+func WithAdminToken(token string) Option {
+	return func(c *Config) { c.Auth.AdminToken = token }
+}
+
+func WithLog(level, format string) Option {
+	return func(c *Config) {
+		c.Log.Level = level
+		c.Log.Format = format
+	}
+}
+
+func WithMaintenance(m MaintenanceConfig) Option {
+	return func(c *Config) { c.Maintenance = m }
+}
+
+func WithDebug(enabled bool) Option {
+	return func(c *Config) { c.Debug.Enabled = enabled }
+}
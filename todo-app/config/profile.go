@@ -0,0 +1,98 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	sharedconfig "github.com/jabeedhexanovamedia/go-echo-practice/pkg/config"
+)
+
+const (
+	EnvDevelopment = "development"
+	EnvTest        = "test"
+	EnvProduction  = "production"
+)
+
+// profile holds the defaults and validation rules for one APP_ENV.
+// Env vars and flags still override every default here.
+type profile struct {
+	DBURI     string
+	LogLevel  string
+	LogFormat string
+	// BindAddr is empty in production to listen on all interfaces
+	BindAddr string
+	// Strict turns on production validation: DB and secrets must be set
+	Strict bool
+}
+
+var profiles = map[string]profile{
+	// local SQLite so a fresh clone runs without a database server
+	EnvDevelopment: {
+		DBURI:     "sqlite://todo.db",
+		LogLevel:  "debug",
+		LogFormat: "text",
+		BindAddr:  "127.0.0.1",
+	},
+	EnvTest: {
+		DBURI:     "sqlite://:memory:",
+		LogLevel:  "warn",
+		LogFormat: "text",
+		BindAddr:  "127.0.0.1",
+	},
+	EnvProduction: {
+		LogLevel:  "info",
+		LogFormat: "json",
+		Strict:    true,
+	},
+}
+
+var envAliases = map[string]string{
+	"dev":  EnvDevelopment,
+	"prod": EnvProduction,
+}
+
+func (p profile) server() ServerConfig {
+	s := sharedconfig.DefaultServer("8080")
+	s.BindAddr = p.BindAddr
+	return s
+}
+
+// profileFor resolves APP_ENV (accepting dev/prod shorthands) to its profile.
+func profileFor(appEnv string) (string, profile, error) {
+	name := strings.ToLower(strings.TrimSpace(appEnv))
+	if alias, ok := envAliases[name]; ok {
+		name = alias
+	}
+	p, ok := profiles[name]
+	if !ok {
+		return "", profile{}, fmt.Errorf("unknown APP_ENV %q (want %s, %s or %s)", appEnv, EnvDevelopment, EnvTest, EnvProduction)
+	}
+	return name, p, nil
+}
+
+// validate applies the profile's rules to the final, fully merged config.
+func (p profile) validate(cfg *Config) error {
+	var errs []error
+
+	if cfg.DB.URI == "" {
+		errs = append(errs, errors.New("DB_URI is required but not set"))
+	}
+	if cfg.Debug.Enabled && cfg.Auth.AdminToken == "" {
+		errs = append(errs, errors.New("DEBUG_ENDPOINTS requires ADMIN_TOKEN, which guards them"))
+	}
+
+	if p.Strict {
+		if cfg.Auth.JWTSecret == "" {
+			errs = append(errs, errors.New("AUTH_JWT_SECRET is required in production"))
+		}
+		if strings.HasPrefix(cfg.DB.URI, "sqlite://") {
+			errs = append(errs, errors.New("SQLite DB_URI is not allowed in production"))
+		}
+		if cfg.Log.Level == "debug" {
+			errs = append(errs, errors.New("LOG_LEVEL=debug is not allowed in production"))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"encoding/json"
+	"net/url"
+)
+
+const redacted = "****"
+
+// Redacted returns a copy of the config with secrets masked, safe for logs
+// and the admin config dump.
+func (c Config) Redacted() Config {
+	out := c
+	out.DB.URI = redactURI(c.DB.URI)
+	out.Auth.JWTSecret = redactSecret(c.Auth.JWTSecret)
+	out.Auth.AdminToken = redactSecret(c.Auth.AdminToken)
+	return out
+}
+
+// String renders the redacted config as indented JSON.
+func (c Config) String() string {
+	b, err := json.MarshalIndent(c.Redacted(), "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(b)
+}
+
+func redactSecret(s string) string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+// redactURI keeps the host and path visible but masks the password, so
+// operators can still see which database was picked.
+func redactURI(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return redacted
+	}
+	return u.Redacted()
+}
@@ -0,0 +1,57 @@
+// Package debug mounts Go's runtime introspection under /debug, for
+// profiling a running todo-app during load tests:
+//
+//	go tool pprof -http=:8081 -H "Authorization: Bearer $ADMIN_TOKEN" \
+//		http://localhost:8080/debug/pprof/profile?seconds=30
+//	curl -H "Authorization: Bearer $ADMIN_TOKEN" localhost:8080/debug/vars
+//
+// Profiles expose source paths and memory contents, so the group is off
+// unless DEBUG_ENDPOINTS=true and always requires ADMIN_TOKEN.
+package debug
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"strconv"
+	"time"
+
+	"github.com/jabeedhexanovamedia/todo-ap/admin"
+	"github.com/jabeedhexanovamedia/todo-ap/config"
+	"github.com/labstack/echo/v5"
+)
+
+// Register mounts /debug/pprof/* and /debug/vars when cfg enables them.
+func Register(e *echo.Echo, cfg *config.Config) {
+	if !cfg.Debug.Enabled || cfg.Auth.AdminToken == "" {
+		return
+	}
+
+	g := e.Group("/debug", admin.RequireToken(cfg.Auth.AdminToken))
+
+	// pprof.Index serves the named profiles (heap, goroutine, block, ...)
+	// from the path after /debug/pprof/
+	g.GET("/pprof/*", echo.WrapHandler(http.HandlerFunc(pprof.Index)))
+	g.GET("/pprof/cmdline", echo.WrapHandler(http.HandlerFunc(pprof.Cmdline)))
+	g.GET("/pprof/profile", echo.WrapHandler(sampling(pprof.Profile)))
+	g.GET("/pprof/trace", echo.WrapHandler(sampling(pprof.Trace)))
+	// only GET: profiles from go1.10 on are symbolized already, and the
+	// app-wide JSON check would refuse pprof's text/plain POST anyway
+	g.GET("/pprof/symbol", echo.WrapHandler(http.HandlerFunc(pprof.Symbol)))
+
+	// expvar's memstats and cmdline, plus anything the app publishes
+	g.GET("/vars", echo.WrapHandler(expvar.Handler()))
+}
+
+// sampling lets a ?seconds=N profile or trace run past the server's
+// WriteTimeout, which would otherwise cut it off after 10s.
+func sampling(h http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secs, err := strconv.Atoi(r.URL.Query().Get("seconds"))
+		if err != nil || secs <= 0 {
+			secs = 30 // pprof's default for profile; trace defaults to 1
+		}
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(time.Duration(secs)*time.Second + 10*time.Second))
+		h(w, r)
+	})
+}
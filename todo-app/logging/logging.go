@@ -0,0 +1,92 @@
+package logging
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/clock"
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/logger"
+	"github.com/jabeedhexanovamedia/todo-ap/config"
+)
+
+// Logger wraps the app's slog.Logger with a level that can be changed at
+// runtime, e.g. bumped to debug for a few minutes from the admin API.
+type Logger struct {
+	*slog.Logger
+
+	level     *slog.LevelVar
+	baseLevel slog.Level
+
+	clock clock.Clock
+	mu    sync.Mutex
+	timer clock.Timer
+}
+
+// New builds the logger from LOG_LEVEL and LOG_FORMAT with the shared
+// pkg/logger, so it writes the same fields as every other module. clk
+// times SetLevelFor's revert; nil means the system clock.
+func New(cfg config.LogConfig, clk clock.Clock) (*Logger, error) {
+	l, lv, err := logger.New(logger.Config{Level: cfg.Level, Format: cfg.Format})
+	if err != nil {
+		return nil, err
+	}
+
+	if clk == nil {
+		clk = clock.Real{}
+	}
+
+	return &Logger{
+		Logger:    l,
+		level:     lv,
+		baseLevel: lv.Level(),
+		clock:     clk,
+	}, nil
+}
+
+// ParseLevel accepts debug, info, warn and error (case-insensitive).
+func ParseLevel(s string) (slog.Level, error) {
+	return logger.ParseLevel(s)
+}
+
+// Level returns the currently active level.
+func (l *Logger) Level() slog.Level {
+	return l.level.Level()
+}
+
+// SetLevelFor switches to level and reverts to the configured level after d,
+// returning when it will. A zero duration makes the change permanent until
+// the next call and returns the zero time: it never reverts.
+func (l *Logger) SetLevelFor(level slog.Level, d time.Duration) time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+
+	l.level.Set(level)
+
+	if d <= 0 {
+		return time.Time{}
+	}
+
+	var t clock.Timer
+	t = l.clock.AfterFunc(d, func() {
+		// Stop cannot recall a callback that already started, so a later
+		// call may have replaced this timer; only the current one reverts.
+		l.mu.Lock()
+		if l.timer != t {
+			l.mu.Unlock()
+			return
+		}
+		l.timer = nil
+		l.level.Set(l.baseLevel)
+		l.mu.Unlock()
+
+		l.Info("log level reverted", "level", l.baseLevel.String())
+	})
+	l.timer = t
+	return l.clock.Now().Add(d)
+}
@@ -0,0 +1,97 @@
+package logging
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/jabeedhexanovamedia/go-echo-practice/pkg/clock"
+	"github.com/jabeedhexanovamedia/todo-ap/config"
+)
+
+func newLogger(t *testing.T, clk clock.Clock) *Logger {
+	t.Helper()
+	l, err := New(config.LogConfig{Level: "error", Format: "json"}, clk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestSetLevelFor(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(start)
+	l := newLogger(t, clk)
+
+	if got := l.SetLevelFor(slog.LevelDebug, 10*time.Minute); !got.Equal(start.Add(10 * time.Minute)) {
+		t.Errorf("reverts at %v, want %v", got, start.Add(10*time.Minute))
+	}
+	clk.Advance(5 * time.Minute)
+	if l.Level() != slog.LevelDebug {
+		t.Fatalf("level = %v before the deadline", l.Level())
+	}
+
+	// a second change replaces the first timer
+	l.SetLevelFor(slog.LevelWarn, 10*time.Minute)
+	clk.Advance(5 * time.Minute)
+	if l.Level() != slog.LevelWarn {
+		t.Fatalf("level = %v; the first timer reverted the second change", l.Level())
+	}
+	clk.Advance(5 * time.Minute)
+	if l.Level() != slog.LevelError {
+		t.Fatalf("level = %v after the deadline, want the configured error", l.Level())
+	}
+}
+
+func TestSetLevelForever(t *testing.T) {
+	clk := clock.NewFake(time.Now())
+	l := newLogger(t, clk)
+
+	l.SetLevelFor(slog.LevelDebug, time.Minute)
+	if got := l.SetLevelFor(slog.LevelInfo, 0); !got.IsZero() {
+		t.Errorf("SetLevelFor(_, 0) = %v, want the zero time", got)
+	}
+	if n := clk.Waiters(); n != 0 {
+		t.Errorf("%d timers pending, want none", n)
+	}
+	clk.Advance(24 * time.Hour)
+	if l.Level() != slog.LevelInfo {
+		t.Errorf("level = %v, want info kept", l.Level())
+	}
+}
+
+// lateClock's timers cannot be stopped, like a time.Timer whose callback
+// has already started when Stop is called.
+type lateClock struct {
+	clock.Real
+	fns []func()
+}
+
+func (c *lateClock) AfterFunc(d time.Duration, f func()) clock.Timer {
+	c.fns = append(c.fns, f)
+	return &lateTimer{n: len(c.fns)}
+}
+
+// lateTimer has a field so that each one has its own address.
+type lateTimer struct{ n int }
+
+func (*lateTimer) Stop() bool { return false }
+
+func TestStaleRevert(t *testing.T) {
+	clk := &lateClock{}
+	l := newLogger(t, clk)
+
+	l.SetLevelFor(slog.LevelDebug, time.Minute)
+	l.SetLevelFor(slog.LevelWarn, time.Hour)
+
+	// the first timer fires after the second call replaced it
+	clk.fns[0]()
+	if l.Level() != slog.LevelWarn {
+		t.Fatalf("level = %v; a stale timer reverted the newer change", l.Level())
+	}
+
+	clk.fns[1]()
+	if l.Level() != slog.LevelError {
+		t.Fatalf("level = %v, want the configured error", l.Level())
+	}
+}
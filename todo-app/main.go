@@ -1,27 +1,36 @@
 package main
 
 import (
-	"fmt"
-
+	"context"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/jabeedhexanovamedia/todo-ap/app"
 	"github.com/jabeedhexanovamedia/todo-ap/config"
-	"github.com/labstack/echo/v5"
-
-	"github.com/labstack/echo/v5/middleware"
+	"github.com/jabeedhexanovamedia/todo-ap/logging"
 )
 
 func main() {
 	cfg := config.LoadConfig()
 
-	e := echo.New()
-	e.Use(middleware.RequestLogger())
+	logger, err := logging.New(cfg.Log, nil)
+	if err != nil {
+		log.Fatalf("failed to set up logging: %v", err)
+	}
+
+	slog.SetDefault(logger.Logger)
+
+	e := app.NewServer(cfg, logger)
 
-	e.GET("/", func(c *echo.Context) error {
-		return c.String(200, "Todo API running in "+cfg.AppEnv+" mode")
-	})
+	// SIGTERM stops accepting and drains in-flight requests; see
+	// config.Server.Serve for zero-downtime deploys with SO_REUSEPORT
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	port := fmt.Sprintf(":%s", cfg.Port)
-	if err := e.Start(port); err != nil {
+	if err := cfg.Server.Serve(ctx, e, logger.Logger); err != nil {
 		e.Logger.Error("failed to start server", "error", err)
 	}
-
 }
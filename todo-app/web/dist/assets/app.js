@@ -0,0 +1,15 @@
+// Served from the same binary as the API, so relative URLs just work.
+fetch("/api")
+  .then((res) => res.text())
+  .then((text) => {
+    document.getElementById("status").textContent = text;
+  })
+  .catch(() => {
+    document.getElementById("status").textContent = "API unreachable";
+  });
+
+fetch("/version")
+  .then((res) => res.json())
+  .then((v) => {
+    document.getElementById("version").textContent = `${v.version} (${(v.commit || "unknown").slice(0, 7)})`;
+  });
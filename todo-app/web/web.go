@@ -0,0 +1,66 @@
+// Package web embeds the frontend into the binary, so the API and UI ship
+// as a single executable.
+package web
+
+import (
+	"embed"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+	"github.com/labstack/echo/v5/middleware"
+)
+
+//go:embed dist
+var dist embed.FS
+
+// routedPrefixes are handled by the router and never fall back to
+// index.html; a missing /assets file must be a 404, not the HTML shell.
+// /admin and /debug are listed although they may not be mounted: with
+// them off, a probe for /debug/pprof/ must get 404, not a 200 page.
+var routedPrefixes = []string{"/api", "/admin", "/debug", "/healthz", "/version", "/assets"}
+
+// Register serves the embedded frontend at / with an index.html fallback for
+// client-side routes. MIME types come from the file extension.
+func Register(e *echo.Echo) {
+	e.Use(cacheHeaders)
+
+	e.StaticFS("/assets", echo.MustSubFS(dist, "dist/assets"))
+
+	e.Use(middleware.StaticWithConfig(middleware.StaticConfig{
+		Filesystem: echo.MustSubFS(dist, "dist"),
+		Root:       ".",
+		HTML5:      true,
+		Skipper:    isRouted,
+	}))
+}
+
+// cacheHeaders caches /assets for a day and makes browsers revalidate the
+// index.html shell, so a new binary's UI shows up on the next reload.
+// Embedded files have no mod time, so there is no Last-Modified to lean on.
+func cacheHeaders(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c *echo.Context) error {
+		p := c.Request().URL.Path
+		switch {
+		case strings.HasPrefix(p, "/assets/"):
+			// only real files; a cached 404 would outlive the next deploy
+			if _, err := fs.Stat(dist, path.Join("dist", p)); err == nil {
+				c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=86400")
+			}
+		case !isRouted(c):
+			c.Response().Header().Set(echo.HeaderCacheControl, "no-cache")
+		}
+		return next(c)
+	}
+}
+
+func isRouted(c *echo.Context) bool {
+	p := c.Request().URL.Path
+	for _, prefix := range routedPrefixes {
+		if p == prefix || strings.HasPrefix(p, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}